@@ -15,6 +15,7 @@ import (
 	"log"
 	"log/slog"
 	"os"
+	"time"
 )
 
 func main() {
@@ -26,11 +27,21 @@ func main() {
 	}
 
 	settings := plot.Settings{
-		FloorBlock:    block.Grass{},
-		BoundaryBlock: block.StainedTerracotta{Colour: item.ColourCyan()},
-		RoadBlock:     block.Concrete{Colour: item.ColourGrey()},
-		PlotWidth:     32,
-		MaximumPlots:  16,
+		FloorBlock:         block.Grass{},
+		BoundaryBlock:      block.StainedTerracotta{Colour: item.ColourCyan()},
+		RoadBlock:          block.Concrete{Colour: item.ColourGrey()},
+		PlotWidth:          32,
+		MaximumPlots:       16,
+		TrashRetention:     time.Hour * 24 * 7,
+		UndoWindow:         time.Minute * 5,
+		MaximumSnapshots:   8,
+		AutoSnapshots:      3,
+		BlockLogRetention:  time.Hour * 24 * 30,
+		MaximumLibrary:     16,
+		MaximumLibrarySize: 4 << 20,
+	}
+	for i, f := range conf.Listeners {
+		conf.Listeners[i] = plot.WrapListener(f)
 	}
 	conf.Generator = func(dim world.Dimension) world.Generator {
 		return plot.NewGenerator(settings)
@@ -53,20 +64,185 @@ func main() {
 	cmd.Register(cmd.New("plot", "Manages plots and their settings.", []string{"p", "plot"},
 		command.Claim{},
 		command.List{},
+		command.ListPlayer{},
 		command.Teleport{},
 		command.Delete{},
+		command.ClearConfirm{},
 		command.Clear{},
+		command.Undo{},
+		command.AutoClaim{},
 		command.Auto{},
+		command.Permission{},
+		command.PermissionList{},
+		command.Role{},
+		command.FlagSet{},
+		command.FlagUnset{},
+		command.FlagList{},
+		command.TransferAccept{},
+		command.TransferDeny{},
+		command.Transfer{},
+		command.AliasClear{},
+		command.Alias{},
+		command.Title{},
+		command.Description{},
+		command.Info{},
+		command.VisitResult{},
+		command.Visit{},
+		command.Deny{},
+		command.Undeny{},
+		command.SetHomeReset{},
+		command.SetHome{},
+		command.Middle{},
+		command.Random{},
+		command.Next{},
+		command.Prev{},
+		command.Like{},
+		command.Rate{},
+		command.Top{},
+		command.Comment{},
+		command.InboxRead{},
+		command.InboxDelete{},
+		command.InboxClear{},
+		command.Inbox{},
+		command.ContestCreate{},
+		command.ContestJudge{},
+		command.ContestDelete{},
+		command.ContestPublish{},
+		command.ContestSubmit{},
+		command.ContestVote{},
+		command.ContestList{},
+		command.ContestInfo{},
+		command.Done{},
+		command.ReviewNext{},
+		command.ReviewApprove{},
+		command.ReviewReject{},
+		command.TagAdd{},
+		command.TagRemove{},
+		command.Search{},
+		command.ExpiredList{},
+		command.ArchiveList{},
+		command.ArchiveRestore{},
+		command.TrashAll{},
+		command.Trash{},
+		command.Restore{},
+		command.SnapshotSave{},
+		command.SnapshotList{},
+		command.SnapshotRestore{},
+		command.SnapshotDelete{},
+		command.Inspect{},
+		command.Rollback{},
+		command.Copy{},
+		command.Move{},
+		command.Swap{},
+		command.Export{},
+		command.Import{},
+		command.TemplateSave{},
+		command.TemplateDelete{},
+		command.TemplateList{},
+		command.LibWand{},
+		command.LibSave{},
+		command.LibPaste{},
+		command.LibList{},
+		command.LibDelete{},
 	))
 
 	s.Listen()
 
+	done := make(chan struct{})
+	go maintain(w, db, done)
+
 	for p := range s.Accept() {
-		p.Handle(plot.NewPlayerHandler(p.UUID(), settings, db))
+		p.Handle(plot.NewPlayerHandler(p, settings, db))
 	}
+	close(done)
 	_ = db.Close()
 }
 
+// maintain archives expired plots, saves automatic snapshots, purges the trash and prunes the block log every
+// hour until the done channel is closed.
+func maintain(w *world.World, db *plot.DB, done <-chan struct{}) {
+	t := time.NewTicker(time.Hour)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			archiveExpired(w, db)
+			autoSnapshot(w, db)
+			if n, err := db.PurgeTrash(); err != nil {
+				slog.Default().Error("purge trash: " + err.Error())
+			} else if n > 0 {
+				slog.Default().Info("purged trash", "n", n)
+			}
+			if n, err := db.PruneBlockLog(); err != nil {
+				slog.Default().Error("prune block log: " + err.Error())
+			} else if n > 0 {
+				slog.Default().Info("pruned block log", "n", n)
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// archiveExpired archives all plots of which the grace period has passed. Every plot is archived in a separate
+// transaction, so that the world is not blocked for long if many plots expired at once.
+func archiveExpired(w *world.World, db *plot.DB) {
+	var (
+		expired []plot.ExpiringPlot
+		err     error
+	)
+	<-w.Exec(func(*world.Tx) {
+		expired, err = db.ExpiredPlots()
+	})
+	if err != nil {
+		slog.Default().Error("archive expired plots: " + err.Error())
+		return
+	}
+	n := 0
+	for _, e := range expired {
+		<-w.Exec(func(tx *world.Tx) {
+			if ok, err := db.ArchiveExpired(tx, e); err != nil {
+				slog.Default().Error("archive expired plots: " + err.Error())
+			} else if ok {
+				n++
+			}
+		})
+	}
+	if n > 0 {
+		slog.Default().Info("archived expired plots", "n", n)
+	}
+}
+
+// autoSnapshot saves the automatic snapshots of all plots that are due one. Every snapshot is saved in a
+// separate transaction, so that the world is not blocked for long if many plots were edited.
+func autoSnapshot(w *world.World, db *plot.DB) {
+	var (
+		due []plot.Position
+		err error
+	)
+	<-w.Exec(func(*world.Tx) {
+		due, err = db.AutoSnapshotDue()
+	})
+	if err != nil {
+		slog.Default().Error("auto snapshot: " + err.Error())
+		return
+	}
+	n := 0
+	for _, pos := range due {
+		<-w.Exec(func(tx *world.Tx) {
+			if ok, err := db.AutoSnapshot(tx, pos); err != nil {
+				slog.Default().Error("auto snapshot: " + err.Error())
+			} else if ok {
+				n++
+			}
+		})
+	}
+	if n > 0 {
+		slog.Default().Info("saved automatic snapshots", "n", n)
+	}
+}
+
 // readConfig reads the configuration from the config.toml file, or creates the
 // file if it does not yet exist.
 func readConfig(log *slog.Logger) (server.Config, error) {
@@ -0,0 +1,62 @@
+package plot
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+// activityPrefix is the prefix of keys that hold the Activity of a player. The key is followed by the UUID of
+// the player.
+const activityPrefix = "plots:activity:"
+
+// Activity holds the times at which a player was last active, as recorded by its PlayerHandler. It is used to
+// find plots of owners that have been inactive for a long time.
+type Activity struct {
+	// LastLogin is the last time at which the player joined or left the server.
+	LastLogin time.Time
+	// LastEdit is the last time at which the player changed a block in any plot. It is updated at most once
+	// every minute.
+	LastEdit time.Time
+}
+
+// LastActive returns the last time at which the player was active, which is the latest of LastLogin and
+// LastEdit.
+func (a Activity) LastActive() time.Time {
+	if a.LastEdit.After(a.LastLogin) {
+		return a.LastEdit
+	}
+	return a.LastLogin
+}
+
+// activityKey returns the key under which the Activity of the player with the UUID passed is stored.
+func activityKey(id uuid.UUID) []byte {
+	return append([]byte(activityPrefix), id[:]...)
+}
+
+// Activity reads the Activity of the player with the UUID passed. False is returned if no activity was ever
+// recorded for the player.
+func (db *DB) Activity(id uuid.UUID) (Activity, bool) {
+	var a Activity
+	val, err := db.ldb.Get(activityKey(id), nil)
+	if err != nil {
+		return a, false
+	}
+	if err := json.Unmarshal(val, &a); err != nil {
+		return a, false
+	}
+	return a, true
+}
+
+// StoreActivity stores the Activity of the player with the UUID passed.
+func (db *DB) StoreActivity(id uuid.UUID, a Activity) error {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("store activity: %w", err)
+	}
+	if err := db.ldb.Put(activityKey(id), b, nil); err != nil {
+		return fmt.Errorf("store activity: %w", err)
+	}
+	return nil
+}
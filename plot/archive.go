@@ -0,0 +1,265 @@
+package plot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"github.com/google/uuid"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// archivePrefix is the prefix of keys that hold an Archive. The key is followed by the UUID of the owner of
+// the plot archived and the time at which it was archived as a big endian uint64.
+const archivePrefix = "plots:archive:"
+
+// ArchiveReason is the reason that a plot was archived.
+type ArchiveReason uint8
+
+const (
+	// ArchiveExpired is the ArchiveReason of plots archived because their owner was inactive for too long.
+	ArchiveExpired ArchiveReason = iota
+	// ArchiveDeleted is the ArchiveReason of plots deleted by their owner. These archives form the trash and
+	// are purged after Settings.TrashRetention using DB.PurgeTrash.
+	ArchiveDeleted
+)
+
+// String ...
+func (r ArchiveReason) String() string {
+	switch r {
+	case ArchiveExpired:
+		return "expired"
+	case ArchiveDeleted:
+		return "deleted"
+	}
+	panic("should never happen")
+}
+
+// ErrArchiveRemoved is returned by DB.RestoreArchive if the Archive was already restored or removed.
+var ErrArchiveRemoved = errors.New("archive was already restored or removed")
+
+// Archive is a plot that was removed from the world, but of which its metadata, its blocks and its votes,
+// comments and snapshots were kept, so that it may be restored later using DB.RestoreArchive.
+type Archive struct {
+	// Pos is the Position that the plot was archived from.
+	Pos Position
+	// Plot is the Plot as it was when it was archived.
+	Plot *Plot
+	// Archived is the time at which the plot was archived.
+	Archived time.Time
+	// Reason is the reason that the plot was archived.
+	Reason ArchiveReason
+	// Records are the votes, comments and snapshots of the plot as they were when it was archived. The files
+	// of the snapshots are kept in the directory returned by DB.archiveSnapshotDir.
+	Records []plotRecord `json:",omitempty"`
+}
+
+// archiveKey returns the key under which the Archive passed is stored.
+func archiveKey(a *Archive) []byte {
+	key := append(append([]byte(archivePrefix), a.Plot.Owner[:]...), make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(key)-8:], uint64(a.Archived.UnixNano()))
+	return key
+}
+
+// archivePath returns the path of the file that holds the blocks of the Archive.
+func (db *DB) archivePath(a *Archive) string {
+	return filepath.Join(db.dir, "archive", fmt.Sprintf("%v_%v.build", a.Plot.Owner, a.Archived.UnixNano()))
+}
+
+// archiveSnapshotDir returns the directory that holds the blocks of the snapshots of the plot in the Archive.
+func (db *DB) archiveSnapshotDir(a *Archive) string {
+	return filepath.Join(db.dir, "archive", fmt.Sprintf("%v_%v_snapshots", a.Plot.Owner, a.Archived.UnixNano()))
+}
+
+// ArchivePlot archives the Plot at the Position passed for the reason passed. The blocks of the plot are saved
+// to disk, after which the plot is removed from the DB and reset in the world. Its votes, comments and snapshots
+// are kept in the Archive. The plot may be restored using RestoreArchive.
+func (db *DB) ArchivePlot(tx *world.Tx, pos Position, p *Plot, reason ArchiveReason) (*Archive, error) {
+	records, err := db.plotRecords(pos)
+	if err != nil {
+		return nil, fmt.Errorf("archive plot: %w", err)
+	}
+	a := &Archive{Pos: pos, Plot: p, Archived: time.Now(), Reason: reason, Records: records}
+	if err := SaveBuild(db.archivePath(a), CaptureBuild(tx, pos, db.settings)); err != nil {
+		return nil, fmt.Errorf("archive plot: %w", err)
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf("archive plot: %w", err)
+	}
+	if err := db.ldb.Put(archiveKey(a), b, nil); err != nil {
+		return nil, fmt.Errorf("archive plot: %w", err)
+	}
+	// The files of the snapshots are moved out of the way before RemovePlot removes them.
+	if err := os.Rename(db.snapshotDir(pos), db.archiveSnapshotDir(a)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("archive plot: %w", err)
+	}
+	if err := db.RemovePlot(pos); err != nil {
+		return nil, fmt.Errorf("archive plot: %w", err)
+	}
+	if err := db.removePlayerPlot(p.Owner, pos); err != nil {
+		return nil, fmt.Errorf("archive plot: %w", err)
+	}
+	pos.Reset(tx, db.settings)
+	pos.SetBorder(tx, db.settings, db.settings.BoundaryBlock)
+	return a, nil
+}
+
+// Archives returns all archives with the ArchiveReason passed of plots of the player with the UUID passed,
+// ordered from oldest to newest. If uuid.Nil is passed, the archives of all players are returned, ordered by
+// owner.
+func (db *DB) Archives(owner uuid.UUID, reason ArchiveReason) ([]*Archive, error) {
+	prefix := []byte(archivePrefix)
+	if owner != uuid.Nil {
+		prefix = append(prefix, owner[:]...)
+	}
+	it := db.ldb.NewIterator(util.BytesPrefix(prefix), nil)
+	defer it.Release()
+
+	var archives []*Archive
+	for it.Next() {
+		var a Archive
+		if err := json.Unmarshal(it.Value(), &a); err != nil {
+			return nil, fmt.Errorf("archives: %w", err)
+		}
+		if a.Reason == reason {
+			archives = append(archives, &a)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("archives: %w", err)
+	}
+	return archives, nil
+}
+
+// RestoreArchive restores the plot in the Archive passed. The plot is restored at the Position it was archived
+// from if that plot is still free, or at the closest free plot otherwise. The Position that the plot was
+// restored at is returned. The votes, comments and snapshots of the plot are restored along with it. The
+// Archive is removed after restoring it.
+func (db *DB) RestoreArchive(tx *world.Tx, a *Archive) (Position, error) {
+	if ok, err := db.ldb.Has(archiveKey(a), nil); err != nil {
+		return Position{}, fmt.Errorf("restore archive: %w", err)
+	} else if !ok {
+		return Position{}, fmt.Errorf("restore archive: %w", ErrArchiveRemoved)
+	}
+	build, err := LoadBuild(db.archivePath(a))
+	if err != nil {
+		return Position{}, fmt.Errorf("restore archive: %w", err)
+	}
+	pos := a.Pos
+	if _, err := db.Plot(pos); !errors.Is(err, leveldb.ErrNotFound) || !db.settings.Claimable(pos) {
+		if pos, err = db.FreePlotNear(a.Pos, 16); err != nil {
+			if pos, err = db.NextFreePlot(Position{}); err != nil {
+				return Position{}, fmt.Errorf("restore archive: %w", err)
+			}
+		}
+	}
+	positions, err := db.PlayerPlots(a.Plot.Owner)
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return Position{}, fmt.Errorf("restore archive: %w", err)
+	}
+	p := a.Plot
+	p.Number = db.FreeNumber(positions)
+	if pos != a.Pos {
+		// The home of the plot is relative to the plot it was set in, so it no longer applies.
+		p.Home = nil
+	}
+	positions = append(positions, pos)
+
+	batch := new(leveldb.Batch)
+	db.index(batch, pos, p)
+	b, err := json.Marshal(p)
+	if err != nil {
+		return Position{}, fmt.Errorf("restore archive: %w", err)
+	}
+	batch.Put(pos.Hash(), b)
+	if b, err = json.Marshal(positions); err != nil {
+		return Position{}, fmt.Errorf("restore archive: %w", err)
+	}
+	batch.Put(p.Owner[:], b)
+	if err := db.putPlotRecords(batch, pos, a.Records); err != nil {
+		return Position{}, fmt.Errorf("restore archive: %w", err)
+	}
+	batch.Delete(archiveKey(a))
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return Position{}, fmt.Errorf("restore archive: %w", err)
+	}
+	db.cache[pos] = p
+	db.owners[p.Owner] = p.OwnerName
+	_ = os.Remove(db.archivePath(a))
+	_ = os.RemoveAll(db.snapshotDir(pos))
+	_ = os.Rename(db.archiveSnapshotDir(a), db.snapshotDir(pos))
+	if h, ok := handlers.Load(p.Owner); ok {
+		_ = h.(*PlayerHandler).ReloadPlotPositions()
+	}
+
+	build.Place(tx, pos, db.settings)
+	pos.SetBorder(tx, db.settings, p.BorderBlock())
+	return pos, nil
+}
+
+// RemoveArchive permanently removes the Archive passed, including the blocks and snapshots saved for it.
+func (db *DB) RemoveArchive(a *Archive) error {
+	if err := db.ldb.Delete(archiveKey(a), nil); err != nil {
+		return fmt.Errorf("remove archive: %w", err)
+	}
+	if err := os.Remove(db.archivePath(a)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove archive: %w", err)
+	}
+	if err := os.RemoveAll(db.archiveSnapshotDir(a)); err != nil {
+		return fmt.Errorf("remove archive: %w", err)
+	}
+	return nil
+}
+
+// TrashExpires returns the time at which the deleted plot in the Archive passed is purged from the trash. The
+// zero time is returned if deleted plots are kept forever.
+func (db *DB) TrashExpires(a *Archive) time.Time {
+	if db.settings.TrashRetention <= 0 {
+		return time.Time{}
+	}
+	return a.Archived.Add(db.settings.TrashRetention)
+}
+
+// PurgeTrash permanently removes all deleted plots that have been in the trash for longer than
+// Settings.TrashRetention. The amount of plots removed is returned.
+func (db *DB) PurgeTrash() (int, error) {
+	if db.settings.TrashRetention <= 0 {
+		return 0, nil
+	}
+	trash, err := db.Archives(uuid.Nil, ArchiveDeleted)
+	if err != nil {
+		return 0, fmt.Errorf("purge trash: %w", err)
+	}
+	n := 0
+	for _, a := range trash {
+		if time.Now().Before(db.TrashExpires(a)) {
+			continue
+		}
+		if err := db.RemoveArchive(a); err != nil {
+			return n, fmt.Errorf("purge trash: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// removePlayerPlot removes the Position passed from the plots of the player with the UUID passed. If the
+// player is online, the plots held by its PlayerHandler are updated too.
+func (db *DB) removePlayerPlot(id uuid.UUID, pos Position) error {
+	if h, ok := handlers.Load(id); ok {
+		h := h.(*PlayerHandler)
+		return h.SetPlotPositions(slices.DeleteFunc(slices.Clone(h.plots), func(other Position) bool { return other == pos }))
+	}
+	positions, err := db.PlayerPlots(id)
+	if err != nil {
+		return err
+	}
+	return db.StorePlayerPlots(id, slices.DeleteFunc(positions, func(other Position) bool { return other == pos }))
+}
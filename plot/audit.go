@@ -0,0 +1,33 @@
+package plot
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// AuditEntry is an entry in the audit trail of a Plot. It records a change made to the ownership of the
+// plot, such as it being claimed or transferred.
+type AuditEntry struct {
+	// Time is the time at which the change was made.
+	Time time.Time
+	// Action is a short name of the change made, such as 'claim' or 'transfer'.
+	Action string
+	// Actor is the UUID of the player that made the change.
+	Actor uuid.UUID
+	// ActorName is the name of the player that made the change at the time the change was made.
+	ActorName string
+	// Details holds additional information on the change, such as the name of the player that a plot was
+	// transferred to.
+	Details string `json:",omitempty"`
+}
+
+// Audit adds a new AuditEntry to the audit trail of the Plot with the current time.
+func (p *Plot) Audit(action string, actor uuid.UUID, actorName, details string) {
+	p.AuditTrail = append(p.AuditTrail, AuditEntry{
+		Time:      time.Now(),
+		Action:    action,
+		Actor:     actor,
+		ActorName: actorName,
+		Details:   details,
+	})
+}
@@ -0,0 +1,243 @@
+package plot
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// blockLogPrefix is the prefix of keys that hold a BlockChange. The key is followed by the hash of the
+// Position of the plot that the block is in, the time of the change as a big endian uint64 and a big endian
+// uint32 sequence number, so that the changes of a plot are ordered by time.
+const blockLogPrefix = "plots:log:"
+
+// blockLogSeq is the sequence number appended to keys of BlockChanges, so that changes made at the same time
+// do not overwrite each other.
+var blockLogSeq atomic.Uint32
+
+// BlockChange is a change of a single block in a plot made by a player.
+type BlockChange struct {
+	// Actor is the UUID of the player that changed the block.
+	Actor uuid.UUID
+	// Pos is the position of the block changed.
+	Pos cube.Pos
+	// Old is the block before the change and New is the block after the change.
+	Old, New world.Block
+	// Time is the time at which the block was changed.
+	Time time.Time
+}
+
+// Description returns a short description of the BlockChange, such as 'placed stone'.
+func (c BlockChange) Description() string {
+	_, oldAir := c.Old.(block.Air)
+	_, newAir := c.New.(block.Air)
+	switch {
+	case oldAir:
+		return "placed " + BlockName(c.New)
+	case newAir:
+		return "broke " + BlockName(c.Old)
+	}
+	return "changed " + BlockName(c.Old) + " to " + BlockName(c.New)
+}
+
+// BlockName returns a readable name of the world.Block passed, such as 'oak_planks'.
+func BlockName(b world.Block) string {
+	name, _ := b.EncodeBlock()
+	return strings.TrimPrefix(name, "minecraft:")
+}
+
+// blockChangeData is the data of a BlockChange as it is stored in the DB.
+type blockChangeData struct {
+	Actor [16]byte       `nbt:"actor"`
+	X     int32          `nbt:"x"`
+	Y     int32          `nbt:"y"`
+	Z     int32          `nbt:"z"`
+	Old   map[string]any `nbt:"old"`
+	New   map[string]any `nbt:"new"`
+}
+
+// blockLogRange returns the range of keys of all changes in the plot at the Position passed made at or after
+// the time passed.
+func blockLogRange(pos Position, since time.Time) *util.Range {
+	r := util.BytesPrefix(append([]byte(blockLogPrefix), pos.Hash()...))
+	if !since.IsZero() {
+		r.Start = binary.BigEndian.AppendUint64(r.Start, uint64(since.UnixNano()))
+	}
+	return r
+}
+
+// encodeBlockEntity encodes a world.Block like encodeBlock, but includes the data of block entities such as
+// chests.
+func encodeBlockEntity(b world.Block) map[string]any {
+	m := encodeBlock(b)
+	if nbter, ok := b.(world.NBTer); ok {
+		m["nbt"] = nbter.EncodeNBT()
+	}
+	return m
+}
+
+// decodeBlockEntity decodes a world.Block previously encoded using encodeBlockEntity.
+func decodeBlockEntity(m map[string]any) world.Block {
+	b := decodeBlock(m)
+	if data, ok := m["nbt"].(map[string]any); ok {
+		if nbter, ok := b.(world.NBTer); ok {
+			b = nbter.DecodeNBT(data).(world.Block)
+		}
+	}
+	return b
+}
+
+// LogBlockChange appends the BlockChange passed to the log of the plot at the Position passed.
+func (db *DB) LogBlockChange(pos Position, c BlockChange) error {
+	key := binary.BigEndian.AppendUint64(append([]byte(blockLogPrefix), pos.Hash()...), uint64(c.Time.UnixNano()))
+	key = binary.BigEndian.AppendUint32(key, blockLogSeq.Add(1))
+
+	b, err := nbt.MarshalEncoding(blockChangeData{
+		Actor: c.Actor,
+		X:     int32(c.Pos[0]), Y: int32(c.Pos[1]), Z: int32(c.Pos[2]),
+		Old: encodeBlockEntity(c.Old), New: encodeBlockEntity(c.New),
+	}, nbt.LittleEndian)
+	if err != nil {
+		return fmt.Errorf("log block change: %w", err)
+	}
+	if err := db.ldb.Put(key, b, nil); err != nil {
+		return fmt.Errorf("log block change: %w", err)
+	}
+	return nil
+}
+
+// BlockChanges calls the function passed for every change in the plot at the Position passed made at or after
+// the time passed, ordered from newest to oldest. If the function returns false, no further changes are read.
+func (db *DB) BlockChanges(pos Position, since time.Time, f func(c BlockChange) bool) error {
+	it := db.ldb.NewIterator(blockLogRange(pos, since), nil)
+	defer it.Release()
+
+	prefix := len(blockLogPrefix) + 8
+	for ok := it.Last(); ok; ok = it.Prev() {
+		var data blockChangeData
+		if err := nbt.UnmarshalEncoding(it.Value(), &data, nbt.LittleEndian); err != nil {
+			return fmt.Errorf("block changes: %w", err)
+		}
+		c := BlockChange{
+			Actor: data.Actor,
+			Pos:   cube.Pos{int(data.X), int(data.Y), int(data.Z)},
+			Old:   decodeBlockEntity(data.Old),
+			New:   decodeBlockEntity(data.New),
+			Time:  time.Unix(0, int64(binary.BigEndian.Uint64(it.Key()[prefix:]))),
+		}
+		if !f(c) {
+			break
+		}
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("block changes: %w", err)
+	}
+	return nil
+}
+
+// BlockHistory returns up to n of the latest changes of the block at the cube.Pos passed, ordered from newest
+// to oldest.
+func (db *DB) BlockHistory(pos cube.Pos, n int) ([]BlockChange, error) {
+	var history []BlockChange
+	err := db.BlockChanges(PosFromBlockPos(pos, db.settings), time.Time{}, func(c BlockChange) bool {
+		if c.Pos == pos {
+			history = append(history, c)
+		}
+		return len(history) < n
+	})
+	return history, err
+}
+
+// Rollback reverts all changes made by the player with the UUID passed in the plot at the Position passed at or
+// after the time passed. Blocks that were changed by other players afterwards are left untouched. The blocks
+// reverted are logged as changes made by the player with the UUID by. The amount of blocks reverted is
+// returned.
+func (db *DB) Rollback(tx *world.Tx, pos Position, actor uuid.UUID, since time.Time, by uuid.UUID) (int, error) {
+	var (
+		// changedByOthers holds the blocks changed by other players after the changes of the actor.
+		changedByOthers = map[cube.Pos]struct{}{}
+		revert          = map[cube.Pos]world.Block{}
+	)
+	err := db.BlockChanges(pos, since, func(c BlockChange) bool {
+		if c.Actor != actor {
+			changedByOthers[c.Pos] = struct{}{}
+		} else if _, ok := changedByOthers[c.Pos]; !ok {
+			// Changes are read from newest to oldest, so the oldest state of the block before the changes of
+			// the actor is the one that remains.
+			revert[c.Pos] = c.Old
+		}
+		return true
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rollback: %w", err)
+	}
+	now := time.Now()
+	for blockPos, b := range revert {
+		c := BlockChange{Actor: by, Pos: blockPos, Old: tx.Block(blockPos), New: b, Time: now}
+		tx.SetBlock(blockPos, b, nil)
+		if err := db.LogBlockChange(pos, c); err != nil {
+			return 0, fmt.Errorf("rollback: %w", err)
+		}
+	}
+	return len(revert), nil
+}
+
+// removeBlockLog adds the deletion of all changes logged in the plot at the Position passed to the batch
+// passed.
+func (db *DB) removeBlockLog(batch *leveldb.Batch, pos Position) {
+	it := db.ldb.NewIterator(blockLogRange(pos, time.Time{}), nil)
+	defer it.Release()
+	for it.Next() {
+		batch.Delete(append([]byte(nil), it.Key()...))
+	}
+}
+
+// PruneBlockLog removes all changes made longer than Settings.BlockLogRetention ago from the logs of all plots.
+// The amount of changes removed is returned.
+func (db *DB) PruneBlockLog() (int, error) {
+	if db.settings.BlockLogRetention <= 0 {
+		return 0, nil
+	}
+	before := uint64(time.Now().Add(-db.settings.BlockLogRetention).UnixNano())
+	it := db.ldb.NewIterator(util.BytesPrefix([]byte(blockLogPrefix)), nil)
+	defer it.Release()
+
+	var (
+		batch  = new(leveldb.Batch)
+		n      int
+		prefix = len(blockLogPrefix) + 8
+	)
+	for ok := it.First(); ok; {
+		key := it.Key()
+		if binary.BigEndian.Uint64(key[prefix:]) >= before {
+			// The changes of a plot are ordered by time, so all remaining changes of this plot are kept.
+			ok = it.Seek(util.BytesPrefix(key[:prefix]).Limit)
+			continue
+		}
+		batch.Delete(append([]byte(nil), key...))
+		n++
+		if batch.Len() >= 4096 {
+			if err := db.ldb.Write(batch, nil); err != nil {
+				return n, fmt.Errorf("prune block log: %w", err)
+			}
+			batch.Reset()
+		}
+		ok = it.Next()
+	}
+	if err := it.Error(); err != nil {
+		return n, fmt.Errorf("prune block log: %w", err)
+	}
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return n, fmt.Errorf("prune block log: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,324 @@
+package plot
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// buildHeight is the height of the part of a plot that is captured in a Build. It matches the height of the
+// area reset by Position.Reset.
+const buildHeight = 256
+
+// Build is a copy of the blocks in a plot, including liquids and the data of block entities such as chests.
+// Builds are created using CaptureBuild and may be placed in any plot again using Build.Place. They may be
+// written to disk in a compact format using Build.WriteTo or SaveBuild.
+type Build struct {
+	width, height, length int
+	palette               []world.Block
+	// blocks holds an index in the palette for every block in the Build. Blocks are ordered by X, then Z and
+	// then Y, so that the blocks of a single column are next to each other.
+	blocks []uint16
+	// liquids holds the liquids present in the same position as another block, such as in waterlogged
+	// blocks, indexed by the index of the block.
+	liquids map[int]world.Liquid
+	// entities holds the blocks that have block entity data, such as chests, indexed by the index of the
+	// block. These blocks are not present in the palette with their data.
+	entities map[int]world.Block
+	// indices maps the runtime IDs of blocks to their index in the palette while the Build is being created.
+	indices map[uint32]uint16
+	// y is the Y at which the lowest layer of the Build was captured.
+	y int
+}
+
+// CaptureBuild captures all blocks within the bounds of the plot at the Position passed and returns them as
+// a Build. The height of the build is limited to the highest block that is not air.
+func CaptureBuild(tx *world.Tx, pos Position, settings Settings) *Build {
+	base, _ := pos.Bounds(settings)
+	height := 0
+	for x := 0; x < settings.PlotWidth; x++ {
+		for z := 0; z < settings.PlotWidth; z++ {
+			height = max(height, tx.HighestBlock(base[0]+x, base[2]+z)+1)
+		}
+	}
+	height = max(min(height, buildHeight), 0)
+	return CaptureRegion(tx, base, base.Add(cube.Pos{settings.PlotWidth - 1, height - 1, settings.PlotWidth - 1}))
+}
+
+// CaptureRegion captures all blocks between the lowest and highest corner passed, inclusive, and returns them
+// as a Build.
+func CaptureRegion(tx *world.Tx, low, high cube.Pos) *Build {
+	b := newBuild(high[0]-low[0]+1, high[1]-low[1]+1, high[2]-low[2]+1)
+	b.y = low[1]
+	for x := 0; x < b.width; x++ {
+		for z := 0; z < b.length; z++ {
+			for y := 0; y < b.height; y++ {
+				blockPos := low.Add(cube.Pos{x, y, z})
+				i := b.index(x, y, z)
+				bl := tx.Block(blockPos)
+				if _, ok := bl.(world.NBTer); ok {
+					b.entities[i] = bl
+				}
+				b.set(i, bl)
+				if _, isLiquid := bl.(world.Liquid); !isLiquid {
+					if liq, ok := tx.Liquid(blockPos); ok {
+						b.liquids[i] = liq
+					}
+				}
+			}
+		}
+	}
+	return b
+}
+
+// newBuild creates an empty Build with the dimensions passed. Its blocks must be set using Build.set.
+func newBuild(width, height, length int) *Build {
+	return &Build{
+		width:    width,
+		height:   height,
+		length:   length,
+		blocks:   make([]uint16, width*height*length),
+		liquids:  map[int]world.Liquid{},
+		entities: map[int]world.Block{},
+		indices:  map[uint32]uint16{},
+	}
+}
+
+// set sets the block at the index passed to the world.Block passed, adding it to the palette if needed. Blocks
+// with block entity data must also be stored in Build.entities.
+func (b *Build) set(i int, bl world.Block) {
+	// Structure voids are stored as nil blocks, which have no runtime ID.
+	rid := uint32(math.MaxUint32)
+	if bl != nil {
+		rid = world.BlockRuntimeID(bl)
+	}
+	n, ok := b.indices[rid]
+	if !ok {
+		n = uint16(len(b.palette))
+		b.indices[rid] = n
+		b.palette = append(b.palette, bl)
+	}
+	b.blocks[i] = n
+}
+
+// index returns the index of the block at the X, Y and Z passed in Build.blocks.
+func (b *Build) index(x, y, z int) int {
+	return (x*b.length+z)*b.height + y
+}
+
+// Dimensions returns the dimensions of the Build. The height returned is always the full height of a plot, so
+// that any blocks above the Build are removed when it is placed.
+func (b *Build) Dimensions() [3]int {
+	return [3]int{b.width, buildHeight, b.length}
+}
+
+// At returns the block and liquid at the position passed. Positions above the blocks captured are air.
+func (b *Build) At(x, y, z int, _ func(x, y, z int) world.Block) (world.Block, world.Liquid) {
+	if y >= b.height {
+		return block.Air{}, nil
+	}
+	i := b.index(x, y, z)
+	bl := b.palette[b.blocks[i]]
+	if e, ok := b.entities[i]; ok {
+		bl = e
+	}
+	return bl, b.liquids[i]
+}
+
+// Place places the Build in the plot at the Position passed, replacing all blocks in the plot. If the Build
+// is larger than the plot, it is cut off at the bounds of the plot.
+func (b *Build) Place(tx *world.Tx, pos Position, settings Settings) {
+	base, _ := pos.Bounds(settings)
+	if b.width > settings.PlotWidth || b.length > settings.PlotWidth {
+		tx.BuildStructure(base, clipped{Build: b, width: settings.PlotWidth})
+		return
+	}
+	tx.BuildStructure(base, b)
+}
+
+// clipped is a Build that is cut off at a specific width and length.
+type clipped struct {
+	*Build
+	width int
+}
+
+// Dimensions ...
+func (c clipped) Dimensions() [3]int {
+	return [3]int{min(c.Build.width, c.width), buildHeight, min(c.Build.length, c.width)}
+}
+
+// buildData is the NBT representation of a Build as written by Build.WriteTo.
+type buildData struct {
+	Width    int32            `nbt:"width"`
+	Height   int32            `nbt:"height"`
+	Length   int32            `nbt:"length"`
+	Y        int32            `nbt:"y"`
+	Palette  []map[string]any `nbt:"palette"`
+	Blocks   []int32          `nbt:"blocks"`
+	Liquids  []map[string]any `nbt:"liquids"`
+	Entities []map[string]any `nbt:"entities"`
+}
+
+// structureVoid is the name of the block encoded for positions in a Build without a block. Blocks at these
+// positions are left unchanged when the Build is placed.
+const structureVoid = "minecraft:structure_void"
+
+// encodeBlock encodes a world.Block to a map holding its name and properties. A nil block is encoded as a
+// structure void.
+func encodeBlock(b world.Block) map[string]any {
+	if b == nil {
+		return map[string]any{"name": structureVoid, "states": map[string]any{}}
+	}
+	name, properties := b.EncodeBlock()
+	return map[string]any{"name": name, "states": properties}
+}
+
+// decodeBlock decodes a world.Block previously encoded using encodeBlock. Structure voids are decoded as nil.
+// Blocks that are unknown, for example because they were removed, are decoded as the default state of a block
+// with the same name, or as air if no block with the name exists.
+func decodeBlock(m map[string]any) world.Block {
+	name, _ := m["name"].(string)
+	properties, _ := m["states"].(map[string]any)
+	if name == structureVoid {
+		return nil
+	}
+	if b, ok := world.BlockByName(name, properties); ok {
+		return b
+	}
+	if b, ok := defaultBlocks()[name]; ok {
+		return b
+	}
+	return block.Air{}
+}
+
+// defaultBlocks returns the first state registered of every block, indexed by the name of the block. It is
+// used to decode blocks with unknown properties, for example from other versions or editions of the game.
+var defaultBlocks = sync.OnceValue(func() map[string]world.Block {
+	m := map[string]world.Block{}
+	for rid := uint32(0); ; rid++ {
+		b, ok := world.BlockByRuntimeID(rid)
+		if !ok {
+			return m
+		}
+		if name, _ := b.EncodeBlock(); m[name] == nil {
+			m[name] = b
+		}
+	}
+})
+
+// WriteTo writes the Build to the io.Writer passed in a compact format, which may be read again using
+// ReadBuild. The blocks are stored as NBT compressed using DEFLATE.
+func (b *Build) WriteTo(w io.Writer) (int64, error) {
+	data := buildData{Width: int32(b.width), Height: int32(b.height), Length: int32(b.length), Y: int32(b.y), Blocks: make([]int32, len(b.blocks))}
+	for _, bl := range b.palette {
+		data.Palette = append(data.Palette, encodeBlock(bl))
+	}
+	for i, n := range b.blocks {
+		data.Blocks[i] = int32(n)
+	}
+	for i, liq := range b.liquids {
+		m := encodeBlock(liq)
+		m["index"] = int32(i)
+		data.Liquids = append(data.Liquids, m)
+	}
+	for i, bl := range b.entities {
+		data.Entities = append(data.Entities, map[string]any{"index": int32(i), "nbt": bl.(world.NBTer).EncodeNBT()})
+	}
+	raw, err := nbt.MarshalEncoding(data, nbt.LittleEndian)
+	if err != nil {
+		return 0, fmt.Errorf("write build: %w", err)
+	}
+	var buf bytes.Buffer
+	fw, _ := flate.NewWriter(&buf, flate.BestCompression)
+	_, _ = fw.Write(raw)
+	if err := fw.Close(); err != nil {
+		return 0, fmt.Errorf("write build: %w", err)
+	}
+	return buf.WriteTo(w)
+}
+
+// ReadBuild reads a Build previously written using Build.WriteTo from the io.Reader passed.
+func ReadBuild(r io.Reader) (*Build, error) {
+	raw, err := io.ReadAll(flate.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("read build: %w", err)
+	}
+	var data buildData
+	if err := nbt.UnmarshalEncoding(raw, &data, nbt.LittleEndian); err != nil {
+		return nil, fmt.Errorf("read build: %w", err)
+	}
+	b := &Build{
+		width:    int(data.Width),
+		height:   int(data.Height),
+		length:   int(data.Length),
+		y:        int(data.Y),
+		blocks:   make([]uint16, len(data.Blocks)),
+		liquids:  map[int]world.Liquid{},
+		entities: map[int]world.Block{},
+	}
+	if b.width < 0 || b.height < 0 || b.length < 0 || b.y < 0 || b.y+b.height > buildHeight || len(data.Blocks) != b.width*b.height*b.length {
+		return nil, fmt.Errorf("read build: invalid dimensions %vx%vx%v at y %v", b.width, b.height, b.length, b.y)
+	}
+	for _, m := range data.Palette {
+		b.palette = append(b.palette, decodeBlock(m))
+	}
+	for i, n := range data.Blocks {
+		if n < 0 || int(n) >= len(b.palette) {
+			return nil, fmt.Errorf("read build: palette index %v out of range", n)
+		}
+		b.blocks[i] = uint16(n)
+	}
+	for _, m := range data.Liquids {
+		i, _ := m["index"].(int32)
+		if liq, ok := decodeBlock(m).(world.Liquid); ok && int(i) < len(b.blocks) {
+			b.liquids[int(i)] = liq
+		}
+	}
+	for _, m := range data.Entities {
+		i, _ := m["index"].(int32)
+		data, _ := m["nbt"].(map[string]any)
+		if int(i) >= len(b.blocks) || i < 0 {
+			continue
+		}
+		if nbter, ok := b.palette[b.blocks[i]].(world.NBTer); ok {
+			b.entities[int(i)] = nbter.DecodeNBT(data).(world.Block)
+		}
+	}
+	return b, nil
+}
+
+// SaveBuild writes the Build passed to a file at the path passed, creating any directories that do not yet
+// exist.
+func SaveBuild(path string, b *Build) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("save build: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("save build: %w", err)
+	}
+	if _, err := b.WriteTo(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadBuild reads a Build from the file at the path passed, as written by SaveBuild.
+func LoadBuild(path string) (*Build, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("load build: %w", err)
+	}
+	defer f.Close()
+	return ReadBuild(f)
+}
@@ -0,0 +1,87 @@
+package plot
+
+import (
+	"bytes"
+	"compress/flate"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"testing"
+)
+
+func TestBuildRoundTrip(t *testing.T) {
+	chest := block.NewChest()
+	chest.Facing, chest.CustomName = cube.South, "Loot"
+	oak := block.Planks{Wood: block.OakWood()}
+
+	b := newBuild(2, 3, 2)
+	b.y = 40
+	for i := range b.blocks {
+		b.set(i, block.Air{})
+	}
+	b.set(b.index(0, 0, 0), block.Stone{})
+	b.set(b.index(1, 0, 1), block.Stairs{Block: oak, Facing: cube.West})
+	b.set(b.index(0, 1, 1), chest)
+	b.entities[b.index(0, 1, 1)] = chest
+	b.set(b.index(1, 2, 0), block.Slab{Block: oak})
+	b.liquids[b.index(1, 2, 0)] = block.Water{Depth: 8, Still: true}
+	b.set(b.index(0, 2, 0), nil)
+
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf); err != nil {
+		t.Fatalf("write build: %v", err)
+	}
+	r, err := ReadBuild(&buf)
+	if err != nil {
+		t.Fatalf("read build: %v", err)
+	}
+	if r.Dimensions() != b.Dimensions() || r.height != b.height || r.Y() != b.Y() {
+		t.Fatalf("read build of %vx%vx%v at y %v, want %vx%vx%v at y %v", r.width, r.height, r.length, r.Y(), b.width, b.height, b.length, b.Y())
+	}
+	for x := 0; x < b.width; x++ {
+		for y := 0; y < b.height; y++ {
+			for z := 0; z < b.length; z++ {
+				want, wantLiq := b.At(x, y, z, nil)
+				got, gotLiq := r.At(x, y, z, nil)
+				if (want == nil) != (got == nil) || (want != nil && world.BlockRuntimeID(got) != world.BlockRuntimeID(want)) {
+					t.Errorf("block at %v %v %v = %v, want %v", x, y, z, got, want)
+				}
+				if (wantLiq == nil) != (gotLiq == nil) || (wantLiq != nil && world.BlockRuntimeID(gotLiq) != world.BlockRuntimeID(wantLiq)) {
+					t.Errorf("liquid at %v %v %v = %v, want %v", x, y, z, gotLiq, wantLiq)
+				}
+			}
+		}
+	}
+	if got, _ := r.At(0, 1, 1, nil); got.(block.Chest).CustomName != "Loot" {
+		t.Errorf("chest has name %q, want \"Loot\"", got.(block.Chest).CustomName)
+	}
+}
+
+func TestReadBuildInvalidDimensions(t *testing.T) {
+	palette := []map[string]any{encodeBlock(block.Stone{})}
+	for _, tc := range []struct {
+		name string
+		data buildData
+	}{
+		{"negative width", buildData{Width: -1, Height: 1, Length: -1, Palette: palette, Blocks: []int32{0}}},
+		{"too few blocks", buildData{Width: 2, Height: 2, Length: 2, Palette: palette, Blocks: []int32{0}}},
+		{"too high", buildData{Width: 1, Height: 1, Length: 1, Y: buildHeight, Palette: palette, Blocks: []int32{0}}},
+		{"negative y", buildData{Width: 1, Height: 1, Length: 1, Y: -1, Palette: palette, Blocks: []int32{0}}},
+		{"palette index", buildData{Width: 1, Height: 1, Length: 1, Palette: palette, Blocks: []int32{1}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := nbt.MarshalEncoding(tc.data, nbt.LittleEndian)
+			if err != nil {
+				t.Fatalf("encode build: %v", err)
+			}
+			var buf bytes.Buffer
+			fw, _ := flate.NewWriter(&buf, flate.BestCompression)
+			_, _ = fw.Write(raw)
+			_ = fw.Close()
+			if _, err := ReadBuild(&buf); err == nil {
+				t.Error("ReadBuild returned no error")
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strings"
+	"time"
+)
+
+// expiredPageSize is the amount of plots shown on a single page of /plot expired list.
+const expiredPageSize = 10
+
+// ExpiredList implements the /plot expired list command. It shows the plots of inactive owners that will be
+// archived once their grace period has passed, ordered by the time at which they are archived. Only admins
+// may list expiring plots.
+type ExpiredList struct {
+	adminOnly
+	Expired cmd.SubCommand    `cmd:"expired"`
+	List    cmd.SubCommand    `cmd:"list"`
+	Page    cmd.Optional[int] `cmd:"page"`
+}
+
+// Run ...
+func (e ExpiredList) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	if h.Settings().ExpiryPeriod <= 0 {
+		output.Errorf("Plot expiry is disabled on this server.")
+		return
+	}
+	expiring, err := h.DB().ExpiringPlots()
+	if err != nil {
+		output.Errorf("Failed reading expiring plots, please try again later. (%v)", err)
+		return
+	}
+	if len(expiring) == 0 {
+		output.Printf(text.Colourf("<green>There are no expiring plots.</green>"))
+		return
+	}
+	pages := (len(expiring) + expiredPageSize - 1) / expiredPageSize
+	page := e.Page.LoadOr(1)
+	if page < 1 || page > pages {
+		output.Errorf("Unknown page %v. (1-%v)", page, pages)
+		return
+	}
+	offset := (page - 1) * expiredPageSize
+
+	var str strings.Builder
+	for _, ex := range expiring[offset:min(offset+expiredPageSize, len(expiring))] {
+		f := ex.Plot.ColourToFormat()
+		archived := "archived at " + formatTime(ex.Expires)
+		if time.Now().After(ex.Expires) {
+			archived = "archived soon"
+		}
+		str.WriteString(text.Colourf("\n<%v>■</%v> <white>%v by %v</white> <grey>(%v), last active %v, %v</grey>", f, f, ex.Plot.Name(), ex.Plot.OwnerName, ex.Pos, formatTime(ex.LastActive), archived))
+	}
+	output.Printf(text.Colourf("<green>Expiring plots (page %v/%v):</green>", page, pages) + str.String())
+}
+
+// ArchiveList implements the /plot archive list command. It lists the plots of the player that were archived,
+// for example because the player was inactive for too long.
+type ArchiveList struct {
+	Archive cmd.SubCommand `cmd:"archive"`
+	List    cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (ArchiveList) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	archives, ok := readArchives(p, h, output)
+	if !ok {
+		return
+	}
+	var str strings.Builder
+	for n, a := range archives {
+		f := a.Plot.ColourToFormat()
+		str.WriteString(text.Colourf("\n<%v>■</%v> <white>%v: %v</white> <grey>(%v), archived at %v (%v)</grey>", f, f, n+1, a.Plot.Name(), a.Pos, formatTime(a.Archived), a.Reason))
+	}
+	output.Printf(text.Colourf("<green>Your archived plots:</green>") + str.String())
+}
+
+// ArchiveRestore implements the /plot archive restore command. It restores one of the archived plots of the
+// player, selected by the number shown in /plot archive list. The plot is restored at its original location
+// if it is still free, or at a free plot nearby otherwise.
+type ArchiveRestore struct {
+	Archive cmd.SubCommand `cmd:"archive"`
+	Restore cmd.SubCommand `cmd:"restore"`
+	Number  int            `cmd:"number"`
+}
+
+// Run ...
+func (a ArchiveRestore) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	archives, ok := readArchives(p, h, output)
+	if !ok {
+		return
+	}
+	if a.Number < 1 || a.Number > len(archives) {
+		output.Errorf("Unknown archived plot with number %v. (1-%v)", a.Number, len(archives))
+		return
+	}
+	if len(h.PlotPositions()) >= h.MaximumPlots() {
+		output.Errorf("You have reached the maximum amount of plot claims. (%v/%v)", len(h.PlotPositions()), h.MaximumPlots())
+		return
+	}
+	archive := archives[a.Number-1]
+	pos, err := h.DB().RestoreArchive(tx, archive)
+	if err != nil {
+		output.Errorf("Failed restoring plot, please try again later. (%v)", err)
+		return
+	}
+	f := archive.Plot.ColourToFormat()
+	if pos != archive.Pos {
+		output.Printf(text.Colourf("<%v>■</%v> <green>The plot was restored at %v, because its original location was claimed.</green>", f, f, pos))
+		return
+	}
+	output.Printf(text.Colourf("<%v>■</%v> <green>The plot was restored at its original location %v.</green>", f, f, pos))
+}
+
+// readArchives reads the archived plots of the player passed. If the player has no archived plots or they
+// could not be read, an error is written to the cmd.Output and false is returned.
+func readArchives(p *player.Player, h *plot.PlayerHandler, output *cmd.Output) ([]*plot.Archive, bool) {
+	archives, err := h.DB().Archives(p.UUID(), plot.ArchiveExpired)
+	if err != nil {
+		output.Errorf("Failed reading archived plots, please try again later. (%v)", err)
+		return nil, false
+	}
+	if len(archives) == 0 {
+		output.Errorf("You don't have any archived plots.")
+		return nil, false
+	}
+	return archives, true
+}
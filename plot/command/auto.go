@@ -9,35 +9,83 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/text"
 )
 
-// Auto implements the /plot auto command. It teleports the user to the nearest unclaimed plot.
+// Auto implements the /plot auto command. It teleports the user to the free plot closest to the spawn of the
+// world, or to the free plot closest to the player if 'here' is passed.
 type Auto struct {
-	Auto cmd.SubCommand `cmd:"auto"`
+	Auto   cmd.SubCommand           `cmd:"auto"`
+	Origin cmd.Optional[autoOrigin] `cmd:"origin"`
 }
 
 // Run ...
-func (a Auto) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+func (a Auto) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
 	p := source.(*player.Player)
 	h, _ := plot.LookupHandler(p)
 
-	pos := plot.PosFromBlockPos(cube.PosFromVec3(p.Position()), h.Settings())
-
-	// We iterate within a growing square, starting at the plots closest to the player and looking up to 16
-	// plots around the player in each direction.
-	for r := 0; r < 16; r++ {
-		for x := -r; x <= r; x++ {
-			for z := -r; z <= r; z++ {
-				if x == -r || x == r || z == -r || z == r {
-					surrounding := pos.Add(plot.Position{r, r})
-					if _, err := h.DB().Plot(surrounding); err == nil {
-						continue
-					}
-					// The plot isn't yet stored, so it's not claimed. We can teleport the player there.
-					p.Teleport(surrounding.TeleportPosition(h.Settings()))
-					output.Printf(text.Colourf("<green>A free plot was successfully found nearby.</green>"))
-					return
-				}
-			}
-		}
+	pos, ok := findFreePlot(p, h, a.Origin.LoadOr("spawn"), output, tx)
+	if !ok {
+		return
 	}
-	output.Errorf("No free plots could be found in a 32x32 square around you.")
+	p.Teleport(pos.TeleportPosition(tx, h.Settings()))
+	output.Printf(text.Colourf("<green>A free plot was successfully found at %v.</green>", pos))
+}
+
+// AutoClaim implements the /plot auto claim command. It finds a free plot like /plot auto, teleports the
+// player to it and claims it immediately.
+type AutoClaim struct {
+	Auto   cmd.SubCommand           `cmd:"auto"`
+	Claim  cmd.SubCommand           `cmd:"claim"`
+	Origin cmd.Optional[autoOrigin] `cmd:"origin"`
+}
+
+// Run ...
+func (a AutoClaim) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	if len(h.PlotPositions()) >= h.MaximumPlots() {
+		output.Errorf("You have reached the maximum amount of plot claims. (%v/%v)", len(h.PlotPositions()), h.MaximumPlots())
+		return
+	}
+	pos, ok := findFreePlot(p, h, a.Origin.LoadOr("spawn"), output, tx)
+	if !ok {
+		return
+	}
+	if claim(p, h, pos, output, tx, nil) {
+		p.Teleport(pos.TeleportPosition(tx, h.Settings()))
+	}
+}
+
+// autoSearchRadius is the radius in plots around the player that is searched with /plot auto here.
+const autoSearchRadius = 64
+
+// findFreePlot finds a free plot around the origin passed, which is either 'spawn' or 'here'. If no plot
+// could be found, an error is written to the cmd.Output and false is returned.
+func findFreePlot(p *player.Player, h *plot.PlayerHandler, origin autoOrigin, output *cmd.Output, tx *world.Tx) (plot.Position, bool) {
+	var (
+		pos plot.Position
+		err error
+	)
+	if origin == "here" {
+		pos, err = h.DB().FreePlotNear(plot.PosFromBlockPos(cube.PosFromVec3(p.Position()), h.Settings()), autoSearchRadius)
+	} else {
+		pos, err = h.DB().NextFreePlot(plot.PosFromBlockPos(tx.World().Spawn(), h.Settings()))
+	}
+	if err != nil {
+		output.Errorf("No free plots could be found. (%v)", err)
+		return pos, false
+	}
+	return pos, true
+}
+
+// autoOrigin is the origin from which /plot auto searches for a free plot.
+type autoOrigin string
+
+// Type ...
+func (autoOrigin) Type() string {
+	return "AutoOrigin"
+}
+
+// Options ...
+func (autoOrigin) Options(cmd.Source) []string {
+	return []string{"spawn", "here"}
 }
@@ -2,7 +2,6 @@ package command
 
 import (
 	"github.com/df-mc/dragonfly/server/block"
-	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/player"
@@ -10,62 +9,72 @@ import (
 	"github.com/df-mc/plots/plot"
 	"github.com/sandertv/gophertunnel/minecraft/text"
 	"math/rand"
+	"time"
 )
 
-// Claim implements the claim command.
+// Claim implements the claim command. If a template is passed, the template is built into the plot after
+// claiming it.
 type Claim struct {
-	Claim cmd.SubCommand `cmd:"claim"`
+	Claim    cmd.SubCommand             `cmd:"claim"`
+	Template cmd.Optional[templateName] `cmd:"template"`
 }
 
 // Run ...
-func (Claim) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+func (c Claim) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
 	p := source.(*player.Player)
 	h, _ := plot.LookupHandler(p)
 
-	blockPos := cube.PosFromVec3(p.Position())
-	pos := plot.PosFromBlockPos(blockPos, h.Settings())
-
-	min, max := pos.Bounds(h.Settings())
-
-	if !plot.Within(blockPos, min, max) {
+	pos, ok := currentPlot(p, h)
+	if !ok {
 		output.Error("You are not currently in a plot.")
 		return
 	}
+	var template *plot.Build
+	if name, ok := c.Template.Load(); ok {
+		if template, ok = loadTemplate(h, string(name), output); !ok {
+			return
+		}
+	}
+	claim(p, h, pos, output, tx, template)
+}
+
+// claim claims the plot at the plot.Position passed for the player.Player passed. If a template is passed,
+// it is built into the plot after claiming it. If the plot cannot be claimed, an error is written to the
+// cmd.Output and false is returned.
+func claim(p *player.Player, h *plot.PlayerHandler, pos plot.Position, output *cmd.Output, tx *world.Tx, template *plot.Build) bool {
 	if current, err := h.DB().Plot(pos); err == nil {
 		output.Errorf("This plot is already claimed by %v.", current.OwnerName)
-		return
+		return false
+	}
+	if !h.Settings().Claimable(pos) {
+		output.Errorf("This plot is reserved and cannot be claimed.")
+		return false
 	}
 	plots := h.Plots()
-	if len(plots) >= h.Settings().MaximumPlots {
-		output.Errorf("You have reached the maximum amount of plot claims. (%v/%v)", len(plots), h.Settings().MaximumPlots)
-		return
+	if len(plots) >= h.MaximumPlots() {
+		output.Errorf("You have reached the maximum amount of plot claims. (%v/%v)", len(plots), h.MaximumPlots())
+		return false
 	}
 	c := generateRandomColour(plots)
 
-	newPlot := &plot.Plot{OwnerName: p.Name(), Owner: p.UUID(), Colour: c.String()}
+	newPlot := &plot.Plot{OwnerName: p.Name(), Owner: p.UUID(), Number: h.DB().FreeNumber(h.PlotPositions()), Colour: c.String(), Claimed: time.Now()}
+	newPlot.Audit("claim", p.UUID(), p.Name(), "")
 	if err := h.DB().StorePlot(pos, newPlot); err != nil {
 		output.Errorf("Failed claiming plot, please try again later. (%v)", err)
-		return
+		return false
 	}
 	if err := h.SetPlotPositions(append(h.PlotPositions(), pos)); err != nil {
 		output.Errorf("Failed claiming plot, please try again later. (%v)", err)
-		return
+		return false
 	}
-	b := block.Concrete{Colour: c}
-	for x := -1; x < h.Settings().PlotWidth+1; x++ {
-		for z := -1; z < h.Settings().PlotWidth+1; z++ {
-			if x == -1 || x == h.Settings().PlotWidth || z == -1 || z == h.Settings().PlotWidth {
-				tx.SetBlock(min.Add(cube.Pos{x, 22, z}), b, opts)
-			}
-		}
+	pos.SetBorder(tx, h.Settings(), block.Concrete{Colour: c})
+	if template != nil {
+		y, _ := pasteHeight(h, template, output)
+		template.Paste(tx, pos, h.Settings(), y)
 	}
 	f := newPlot.ColourToFormat()
-	output.Printf(text.Colourf("<%v>■</%v> <green>Successfully claimed the plot. (%v/%v)</green>", f, f, len(plots)+1, h.Settings().MaximumPlots))
-}
-
-var opts = &world.SetOpts{
-	DisableBlockUpdates:       true,
-	DisableLiquidDisplacement: true,
+	output.Printf(text.Colourf("<%v>■</%v> <green>Successfully claimed the plot. (%v/%v)</green>", f, f, len(plots)+1, h.MaximumPlots()))
+	return true
 }
 
 // generateRandomColour generates a random colour based on the colours of existing plots. Where possible, a
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"errors"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/player"
@@ -10,13 +11,13 @@ import (
 )
 
 // Clear implements the /plot clear command. It may be used to clear one's plot without removing the claim
-// from it.
+// from it. Clearing the plot must be confirmed using /plot clear confirm.
 type Clear struct {
 	Clear cmd.SubCommand `cmd:"clear"`
 }
 
 // Run ...
-func (r Clear) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+func (Clear) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
 	p := source.(*player.Player)
 	h, _ := plot.LookupHandler(p)
 
@@ -34,7 +35,84 @@ func (r Clear) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
 		output.Errorf("You cannot clear this plot because you do not own it.")
 		return
 	}
+	if !editable(h, pos, output) {
+		return
+	}
+	h.RequestClear(pos)
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <yellow>This will remove all blocks in the plot. Use /p clear confirm within 30 seconds to clear it.</yellow>", f, f))
+}
+
+// ClearConfirm implements the /plot clear confirm command. It clears the plot that the player requested to
+// clear using /plot clear. The contents of the plot are kept for a while, so that clearing it may be undone
+// using /plot undo.
+type ClearConfirm struct {
+	Clear   cmd.SubCommand `cmd:"clear"`
+	Confirm cmd.SubCommand `cmd:"confirm"`
+}
+
+// Run ...
+func (ClearConfirm) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, ok := h.ClearRequest()
+	if !ok {
+		output.Errorf("You have not requested to clear a plot in the last 30 seconds. Use /p clear first.")
+		return
+	}
+	current, err := h.DB().Plot(pos)
+	if err != nil || current.Owner != p.UUID() {
+		output.Errorf("You cannot clear this plot because you do not own it.")
+		return
+	}
+	if !editable(h, pos, output) {
+		return
+	}
+	build := plot.CaptureBuild(tx, pos, h.Settings())
 	pos.Reset(tx, h.Settings())
+	h.SetUndo("clear", func(tx *world.Tx) error {
+		if pl, err := h.DB().Plot(pos); err != nil || pl.Owner != p.UUID() {
+			return errors.New("you no longer own the plot")
+		}
+		if h.DB().Locked(pos) {
+			return errors.New("the plot can no longer be edited")
+		}
+		build.Place(tx, pos, h.Settings())
+		return nil
+	})
 	f := current.ColourToFormat()
-	output.Printf(text.Colourf("<%v>■</%v> <green>Successfully cleared the plot.</green>", f, f))
+	output.Printf(text.Colourf("<%v>■</%v> <green>Successfully cleared the plot.</green>%v", f, f, undoHint(h)))
+}
+
+// Undo implements the /plot undo command. It undoes the last time the player cleared or deleted a plot, if
+// this was recently enough.
+type Undo struct {
+	Undo cmd.SubCommand `cmd:"undo"`
+}
+
+// Run ...
+func (Undo) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	action, undo, ok := h.Undo()
+	if !ok {
+		output.Errorf("There is nothing to undo.")
+		return
+	}
+	if err := undo(tx); err != nil {
+		output.Errorf("Failed undoing %v, please try again later. (%v)", action, err)
+		return
+	}
+	output.Printf(text.Colourf("<green>Successfully undid %v.</green>", action))
+}
+
+// undoHint returns a hint that an action may be undone using /plot undo, or an empty string if actions cannot
+// be undone on the server.
+func undoHint(h *plot.PlayerHandler) string {
+	if h.Settings().UndoWindow <= 0 {
+		return ""
+	}
+	return text.Colourf(" <green>Use /p undo within %v to undo this.</green>", h.Settings().UndoWindow)
 }
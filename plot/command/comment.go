@@ -0,0 +1,196 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strings"
+	"time"
+)
+
+// Comment implements the /plot comment command. It leaves a comment on the plot that the player is currently
+// in, which the owner of the plot may read in its inbox.
+type Comment struct {
+	Comment cmd.SubCommand `cmd:"comment"`
+	Text    cmd.Varargs    `cmd:"text"`
+}
+
+// Run ...
+func (c Comment) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	msg := strings.TrimSpace(string(c.Text))
+	if msg == "" {
+		output.Errorf("The comment may not be empty.")
+		return
+	}
+	if len(msg) > plot.MaxCommentLength {
+		output.Errorf("The comment may be at most %v characters long.", plot.MaxCommentLength)
+		return
+	}
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Errorf("You are not currently in a plot.")
+		return
+	}
+	current, err := h.DB().Plot(pos)
+	if err != nil {
+		output.Errorf("This plot is not claimed.")
+		return
+	}
+	if current.Owner == p.UUID() {
+		output.Errorf("You cannot comment on your own plot.")
+		return
+	}
+	if remaining, ok := h.AllowComment(); !ok {
+		output.Errorf("You must wait %v before leaving another comment.", remaining.Round(time.Second))
+		return
+	}
+	if err := h.DB().AddComment(pos, plot.Comment{Author: p.UUID(), AuthorName: p.Name(), Text: msg, Time: time.Now()}); err != nil {
+		output.Errorf("Failed leaving comment, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Your comment was left on %v by %v.</green>", f, f, current.Name(), current.OwnerName))
+
+	if owner, ok := onlinePlayer(tx, current.Owner); ok {
+		owner.Message(text.Colourf("<%v>■</%v> <yellow>%v left a comment on %v. Use /p inbox to read it.</yellow>", f, f, p.Name(), current.Name()))
+	}
+}
+
+// Inbox implements the /plot inbox command. It shows the comments left on the plots of the player, newest
+// first, and marks them as read.
+type Inbox struct {
+	Inbox cmd.SubCommand    `cmd:"inbox"`
+	Page  cmd.Optional[int] `cmd:"page"`
+}
+
+// inboxPageSize is the amount of comments shown on a single page of /plot inbox.
+const inboxPageSize = 8
+
+// Run ...
+func (i Inbox) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	inbox, ok := readInbox(h, p, output)
+	if !ok {
+		return
+	}
+	pages := (len(inbox) + inboxPageSize - 1) / inboxPageSize
+	page := i.Page.LoadOr(1)
+	if page < 1 || page > pages {
+		output.Errorf("Unknown page %v. (1-%v)", page, pages)
+		return
+	}
+	offset := (page - 1) * inboxPageSize
+	comments := inbox[offset:min(offset+inboxPageSize, len(inbox))]
+
+	var str strings.Builder
+	for n, c := range comments {
+		status := ""
+		if !c.Read {
+			status = text.Colourf(" <yellow>(new)</yellow>")
+		}
+		name := c.Plot.String()
+		if pl, err := h.DB().Plot(c.Plot); err == nil {
+			name = pl.Name()
+		}
+		str.WriteString(text.Colourf("\n<white>%v:</white> <grey>%v on %v, %v:</grey>%v\n   <white>%v</white>", offset+n+1, c.AuthorName, name, formatTime(c.Time), status, c.Text))
+	}
+	output.Printf(text.Colourf("<green>Your inbox (page %v/%v):</green>", page, pages) + str.String())
+	if err := h.DB().MarkRead(comments); err != nil {
+		output.Errorf("Failed marking comments as read, please try again later. (%v)", err)
+	}
+}
+
+// InboxRead implements the /plot inbox read command. It marks all comments in the inbox of the player as
+// read without showing them.
+type InboxRead struct {
+	Inbox cmd.SubCommand `cmd:"inbox"`
+	Read  cmd.SubCommand `cmd:"read"`
+}
+
+// Run ...
+func (InboxRead) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	inbox, ok := readInbox(h, p, output)
+	if !ok {
+		return
+	}
+	if err := h.DB().MarkRead(inbox); err != nil {
+		output.Errorf("Failed marking comments as read, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>All comments in your inbox were marked as read.</green>"))
+}
+
+// InboxDelete implements the /plot inbox delete command. It deletes a single comment, selected by the number
+// shown in /plot inbox, from the inbox of the player.
+type InboxDelete struct {
+	Inbox  cmd.SubCommand `cmd:"inbox"`
+	Delete cmd.SubCommand `cmd:"delete"`
+	Number int            `cmd:"number"`
+}
+
+// Run ...
+func (i InboxDelete) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	inbox, ok := readInbox(h, p, output)
+	if !ok {
+		return
+	}
+	if i.Number < 1 || i.Number > len(inbox) {
+		output.Errorf("Unknown comment with number %v. (1-%v)", i.Number, len(inbox))
+		return
+	}
+	if err := h.DB().DeleteComments(inbox[i.Number-1 : i.Number]); err != nil {
+		output.Errorf("Failed deleting comment, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>Comment %v was deleted from your inbox.</green>", i.Number))
+}
+
+// InboxClear implements the /plot inbox clear command. It deletes all comments from the inbox of the player.
+type InboxClear struct {
+	Inbox cmd.SubCommand `cmd:"inbox"`
+	Clear cmd.SubCommand `cmd:"clear"`
+}
+
+// Run ...
+func (InboxClear) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	inbox, ok := readInbox(h, p, output)
+	if !ok {
+		return
+	}
+	if err := h.DB().DeleteComments(inbox); err != nil {
+		output.Errorf("Failed clearing inbox, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>All %v comment(s) were deleted from your inbox.</green>", len(inbox)))
+}
+
+// readInbox reads the inbox of the player passed. If it could not be read or if it is empty, an error is
+// written to the cmd.Output and false is returned.
+func readInbox(h *plot.PlayerHandler, p *player.Player, output *cmd.Output) ([]plot.Comment, bool) {
+	inbox, err := h.DB().Inbox(p.UUID())
+	if err != nil {
+		output.Errorf("Failed reading inbox, please try again later. (%v)", err)
+		return nil, false
+	}
+	if len(inbox) == 0 {
+		output.Errorf("Your inbox is empty.")
+		return nil, false
+	}
+	return inbox, true
+}
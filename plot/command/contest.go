@@ -0,0 +1,374 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContestCreate implements the /plot contest create command. It creates a new contest with a theme that is
+// open for submissions immediately. Only admins may create contests.
+type ContestCreate struct {
+	adminOnly
+	Contest cmd.SubCommand `cmd:"contest"`
+	Create  cmd.SubCommand `cmd:"create"`
+	Name    string         `cmd:"name"`
+	// Submissions is the duration of the submission window, such as '7d' or '12h'.
+	Submissions string `cmd:"submissions"`
+	// Voting is the duration of the voting after the submission window closes.
+	Voting string      `cmd:"voting"`
+	Theme  cmd.Varargs `cmd:"theme"`
+}
+
+// Run ...
+func (c ContestCreate) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	if !aliasRegex.MatchString(c.Name) {
+		output.Errorf("The name must be 3-16 characters long, start with a letter and may only contain letters, numbers, '-' and '_'.")
+		return
+	}
+	if _, ok := h.DB().Contest(c.Name); ok {
+		output.Errorf("A contest with the name %v already exists.", c.Name)
+		return
+	}
+	submissions, err := parseDuration(c.Submissions)
+	if err != nil {
+		output.Errorf("Invalid submission duration %v. Use a duration such as 7d or 12h.", c.Submissions)
+		return
+	}
+	voting, err := parseDuration(c.Voting)
+	if err != nil {
+		output.Errorf("Invalid voting duration %v. Use a duration such as 7d or 12h.", c.Voting)
+		return
+	}
+	now := time.Now()
+	contest := &plot.Contest{
+		Name:      c.Name,
+		Theme:     strings.TrimSpace(string(c.Theme)),
+		Start:     now,
+		End:       now.Add(submissions),
+		VotingEnd: now.Add(submissions + voting),
+	}
+	if err := h.DB().StoreContest(contest); err != nil {
+		output.Errorf("Failed creating contest, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>Contest %v with the theme '%v' was created. Submissions close at %v and voting ends at %v.</green>", contest.Name, contest.Theme, formatTime(contest.End), formatTime(contest.VotingEnd)))
+}
+
+// ContestJudge implements the /plot contest judge command. It adds a judge to a contest, or removes the judge
+// if it was already added. If a contest has judges, only the judges may vote. Only admins may change judges.
+type ContestJudge struct {
+	adminOnly
+	Contest cmd.SubCommand `cmd:"contest"`
+	Judge   cmd.SubCommand `cmd:"judge"`
+	Name    contestName    `cmd:"name"`
+	Player  ownerName      `cmd:"player"`
+}
+
+// Run ...
+func (c ContestJudge) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	contest, ok := lookupContest(h, c.Name, output)
+	if !ok {
+		return
+	}
+	id, err := h.DB().PlayerByName(string(c.Player))
+	if err != nil {
+		output.Errorf("Unknown player %v.", c.Player)
+		return
+	}
+	verb := "added to"
+	if i := slices.Index(contest.Judges, id); i != -1 {
+		contest.Judges = slices.Delete(contest.Judges, i, i+1)
+		verb = "removed from"
+	} else {
+		contest.Judges = append(contest.Judges, id)
+	}
+	if err := h.DB().StoreContest(contest); err != nil {
+		output.Errorf("Failed changing judges, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>%v was %v the judges of %v.</green>", c.Player, verb, contest.Name))
+}
+
+// ContestDelete implements the /plot contest delete command. It deletes a contest including all of its
+// entries and votes. Only admins may delete contests.
+type ContestDelete struct {
+	adminOnly
+	Contest cmd.SubCommand `cmd:"contest"`
+	Delete  cmd.SubCommand `cmd:"delete"`
+	Name    contestName    `cmd:"name"`
+}
+
+// Run ...
+func (c ContestDelete) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	contest, ok := lookupContest(h, c.Name, output)
+	if !ok {
+		return
+	}
+	if err := h.DB().RemoveContest(contest.Name); err != nil {
+		output.Errorf("Failed deleting contest, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>Contest %v was deleted.</green>", contest.Name))
+}
+
+// ContestPublish implements the /plot contest publish command. It publishes the results of a contest that
+// has finished to all players online. Only admins may publish results.
+type ContestPublish struct {
+	adminOnly
+	Contest cmd.SubCommand `cmd:"contest"`
+	Publish cmd.SubCommand `cmd:"publish"`
+	Name    contestName    `cmd:"name"`
+}
+
+// contestWinners is the amount of entries of a contest that are listed as winners when publishing results.
+const contestWinners = 3
+
+// Run ...
+func (c ContestPublish) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	contest, ok := lookupContest(h, c.Name, output)
+	if !ok {
+		return
+	}
+	if contest.Phase(time.Now()) != plot.ContestFinished {
+		output.Errorf("The results of %v cannot be published until voting ends at %v.", contest.Name, formatTime(contest.VotingEnd))
+		return
+	}
+	contest.Published = true
+	if err := h.DB().StoreContest(contest); err != nil {
+		output.Errorf("Failed publishing results, please try again later. (%v)", err)
+		return
+	}
+	msg := text.Colourf("<green>The results of the contest %v (%v) are in!</green>", contest.Name, contest.Theme) + contestResults(contest, contestWinners)
+	for e := range tx.Players() {
+		e.(*player.Player).Message(msg)
+	}
+}
+
+// ContestSubmit implements the /plot contest submit command. It submits the plot that the player is currently
+// in, which it must own, to a contest. The plot cannot be edited until the submission window closes.
+type ContestSubmit struct {
+	Contest cmd.SubCommand `cmd:"contest"`
+	Submit  cmd.SubCommand `cmd:"submit"`
+	Name    contestName    `cmd:"name"`
+}
+
+// Run ...
+func (c ContestSubmit) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	contest, ok := lookupContest(h, c.Name, output)
+	if !ok {
+		return
+	}
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	if err := contest.Submit(pos, current); err != nil {
+		switch {
+		case errors.Is(err, plot.ErrContestPhase):
+			output.Errorf("%v is not open for submissions.", contest.Name)
+		case errors.Is(err, plot.ErrAlreadySubmitted):
+			output.Errorf("You already submitted a plot to %v.", contest.Name)
+		}
+		return
+	}
+	if err := h.DB().StoreContest(contest); err != nil {
+		output.Errorf("Failed submitting plot, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Your plot was submitted to %v. It cannot be edited until submissions close at %v.</green>", f, f, contest.Name, formatTime(contest.End)))
+}
+
+// ContestVote implements the /plot contest vote command. It votes for the plot that the player is currently
+// in. Each player has a single vote per contest, which may be changed until voting ends.
+type ContestVote struct {
+	Contest cmd.SubCommand `cmd:"contest"`
+	Vote    cmd.SubCommand `cmd:"vote"`
+	Name    contestName    `cmd:"name"`
+}
+
+// Run ...
+func (c ContestVote) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	contest, ok := lookupContest(h, c.Name, output)
+	if !ok {
+		return
+	}
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Errorf("You are not currently in a plot.")
+		return
+	}
+	if err := contest.Vote(p.UUID(), pos); err != nil {
+		switch {
+		case errors.Is(err, plot.ErrContestPhase):
+			output.Errorf("%v is not open for voting.", contest.Name)
+		case errors.Is(err, plot.ErrNotJudge):
+			output.Errorf("Only judges may vote in %v.", contest.Name)
+		case errors.Is(err, plot.ErrNotSubmitted):
+			output.Errorf("This plot was not submitted to %v.", contest.Name)
+		case errors.Is(err, plot.ErrOwnEntry):
+			output.Errorf("You cannot vote for your own plot.")
+		}
+		return
+	}
+	if err := h.DB().StoreContest(contest); err != nil {
+		output.Errorf("Failed voting, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>You voted for plot %v in %v.</green>", pos, contest.Name))
+}
+
+// ContestList implements the /plot contest list command. It lists all contests and the phase they are in.
+type ContestList struct {
+	Contest cmd.SubCommand `cmd:"contest"`
+	List    cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (ContestList) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	contests := h.DB().Contests()
+	if len(contests) == 0 {
+		output.Errorf("There are no contests.")
+		return
+	}
+	var str strings.Builder
+	now := time.Now()
+	for _, c := range contests {
+		str.WriteString(text.Colourf("\n<white>%v:</white> <grey>%v, %v, %v entries</grey>", c.Name, c.Theme, c.Phase(now), len(c.Entries)))
+	}
+	output.Printf(text.Colourf("<green>Contests:</green>") + str.String())
+}
+
+// ContestInfo implements the /plot contest info command. It shows the theme, times and entries of a contest,
+// and the results if they were published.
+type ContestInfo struct {
+	Contest cmd.SubCommand `cmd:"contest"`
+	Info    cmd.SubCommand `cmd:"info"`
+	Name    contestName    `cmd:"name"`
+}
+
+// Run ...
+func (c ContestInfo) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	contest, ok := lookupContest(h, c.Name, output)
+	if !ok {
+		return
+	}
+	var str strings.Builder
+	str.WriteString(text.Colourf("<green>Contest %v</green> <grey>(%v)</grey>", contest.Name, contest.Phase(time.Now())))
+	str.WriteString(text.Colourf("\n<white>Theme:</white> <grey>%v</grey>", contest.Theme))
+	str.WriteString(text.Colourf("\n<white>Submissions:</white> <grey>%v to %v</grey>", formatTime(contest.Start), formatTime(contest.End)))
+	str.WriteString(text.Colourf("\n<white>Voting:</white> <grey>%v to %v</grey>", formatTime(contest.End), formatTime(contest.VotingEnd)))
+	str.WriteString(text.Colourf("\n<white>Judges:</white> <grey>%v</grey>", listOrNone(playerNames(h.DB(), contest.Judges))))
+	if contest.Published {
+		str.WriteString(text.Colourf("\n<white>Results:</white>") + contestResults(contest, len(contest.Entries)))
+	} else {
+		entries := make([]string, 0, len(contest.Entries))
+		for _, e := range contest.Entries {
+			entries = append(entries, fmt.Sprintf("%v by %v", e.Plot, e.OwnerName))
+		}
+		str.WriteString(text.Colourf("\n<white>Entries:</white> <grey>%v</grey>", listOrNone(entries)))
+	}
+	output.Printf(str.String())
+}
+
+// lookupContest looks up the contest with the name passed. If it does not exist, an error is written to the
+// cmd.Output and false is returned.
+func lookupContest(h *plot.PlayerHandler, name contestName, output *cmd.Output) (*plot.Contest, bool) {
+	c, ok := h.DB().Contest(string(name))
+	if !ok {
+		output.Errorf("Unknown contest %v. Use /p contest list to get a list of contests.", name)
+	}
+	return c, ok
+}
+
+// contestResults returns the first n results of the plot.Contest passed as lines of text.
+func contestResults(c *plot.Contest, n int) string {
+	var str strings.Builder
+	for i, e := range c.Results()[:min(n, len(c.Entries))] {
+		str.WriteString(text.Colourf("\n<white>%v.</white> <grey>Plot %v by %v with %v vote(s)</grey>", i+1, e.Plot, e.OwnerName, e.Votes))
+	}
+	return str.String()
+}
+
+// parseDuration parses a duration such as '12h' or '30m' like time.ParseDuration, but also accepts a number
+// of days, such as '7d'.
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid number of days %v", days)
+		}
+		return time.Duration(n) * time.Hour * 24, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err == nil && d <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return d, err
+}
+
+// contestName is the name of a plot.Contest.
+type contestName string
+
+// Type ...
+func (contestName) Type() string {
+	return "ContestName"
+}
+
+// Parse reads any contest name, so that a helpful error may be shown for contests that do not exist.
+func (contestName) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	v.SetString(arg)
+	return nil
+}
+
+// Options returns the names of all contests.
+func (contestName) Options(source cmd.Source) []string {
+	h, ok := plot.LookupHandler(source.(*player.Player))
+	if !ok {
+		return nil
+	}
+	contests := h.DB().Contests()
+	names := make([]string, 0, len(contests))
+	for _, c := range contests {
+		names = append(names, c.Name)
+	}
+	return names
+}
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"fmt"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/player"
@@ -9,7 +10,8 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/text"
 )
 
-// Delete implements a /p delete command, which may be used to clear a plot and delete the claim.
+// Delete implements a /p delete command, which may be used to clear a plot and delete the claim. The plot is
+// moved to the trash, from which it may be restored using /p restore.
 type Delete struct {
 	Delete cmd.SubCommand `cmd:"delete"`
 }
@@ -33,31 +35,24 @@ func (d Delete) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
 		output.Errorf("You cannot delete this plot because you do not own it.")
 		return
 	}
-	plots := h.Plots()
-
-	if err := h.DB().RemovePlot(pos); err != nil {
-		output.Errorf("Failed deleting plot, please try again later. (%v)", err)
+	if h.DB().ContestLocked(pos) {
+		output.Errorf("This plot was submitted to a contest and cannot be deleted until submissions close.")
 		return
 	}
-	newPositions := make([]plot.Position, 0, len(plots)-1)
-	for _, plotPos := range h.PlotPositions() {
-		if plotPos == pos {
-			continue
-		}
-		newPositions = append(newPositions, plotPos)
-	}
-	if err := h.SetPlotPositions(newPositions); err != nil {
+	plots := h.Plots()
+
+	archive, err := h.DB().ArchivePlot(tx, pos, current, plot.ArchiveDeleted)
+	if err != nil {
 		output.Errorf("Failed deleting plot, please try again later. (%v)", err)
 		return
 	}
-	pos.Reset(tx, h.Settings())
-	for x := -1; x < h.Settings().PlotWidth+1; x++ {
-		for z := -1; z < h.Settings().PlotWidth+1; z++ {
-			if x == -1 || x == h.Settings().PlotWidth || z == -1 || z == h.Settings().PlotWidth {
-				tx.SetBlock(min.Add(cube.Pos{x, 22, z}), h.Settings().BoundaryBlock, opts)
-			}
+	h.SetUndo("delete", func(tx *world.Tx) error {
+		if len(h.PlotPositions()) >= h.MaximumPlots() {
+			return fmt.Errorf("you have reached the maximum amount of plot claims (%v/%v)", len(h.PlotPositions()), h.MaximumPlots())
 		}
-	}
+		_, err := h.DB().RestoreArchive(tx, archive)
+		return err
+	})
 	f := current.ColourToFormat()
-	output.Printf(text.Colourf("<%v>■</%v> <green>Successfully deleted the plot. (%v/%v)</green>", f, f, len(plots)-1, h.Settings().MaximumPlots))
+	output.Printf(text.Colourf("<%v>■</%v> <green>Successfully deleted the plot. (%v/%v) Use /p restore to restore it.</green>%v", f, f, len(plots)-1, h.MaximumPlots(), undoHint(h)))
 }
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"slices"
+)
+
+// Deny implements the /plot deny command. It may be used by the owner of a plot to deny a player from entering
+// or visiting the plot. Players currently in the plot are teleported out of it.
+type Deny struct {
+	Deny cmd.SubCommand `cmd:"deny"`
+	Name string         `cmd:"player"`
+}
+
+// Run ...
+func (d Deny) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	id, err := h.DB().PlayerByName(d.Name)
+	if err != nil {
+		output.Errorf("Unknown player %v.", d.Name)
+		return
+	}
+	if id == p.UUID() {
+		output.Error("You cannot deny yourself from your own plot.")
+		return
+	}
+	if current.Denies(id) {
+		output.Errorf("%v is already denied from this plot.", d.Name)
+		return
+	}
+	current.SetRole(id, plot.RoleVisitor)
+	current.Denied = append(current.Denied, id)
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed denying player, please try again later. (%v)", err)
+		return
+	}
+	for e := range tx.Players() {
+		if denied := e.(*player.Player); denied.UUID() == id {
+			if deniedHandler, ok := plot.LookupHandler(denied); ok {
+				if other, ok := currentPlot(denied, deniedHandler); ok && other == pos {
+					denied.Teleport(pos.TeleportPosition(tx, h.Settings()))
+				}
+			}
+			denied.Message(text.Colourf("<red>You were denied from the plot of %v.</red>", p.Name()))
+		}
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>%v can no longer enter this plot.</green>", f, f, d.Name))
+}
+
+// Undeny implements the /plot undeny command. It allows a player previously denied from a plot to enter it
+// again.
+type Undeny struct {
+	Undeny cmd.SubCommand `cmd:"undeny"`
+	Name   string         `cmd:"player"`
+}
+
+// Run ...
+func (u Undeny) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	id, err := h.DB().PlayerByName(u.Name)
+	if err != nil || !current.Denies(id) {
+		output.Errorf("%v is not denied from this plot.", u.Name)
+		return
+	}
+	current.Denied = slices.DeleteFunc(current.Denied, func(other uuid.UUID) bool { return other == id })
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed allowing player, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>%v can enter this plot again.</green>", f, f, u.Name))
+}
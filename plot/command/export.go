@@ -0,0 +1,41 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"path/filepath"
+)
+
+// Export implements the /plot export command. It exports the blocks of the plot that the player is currently
+// in, which it must own, to a Bedrock Edition .mcstructure file, so that the build may be loaded on other
+// servers or using structure blocks. Admins may export any plot.
+type Export struct {
+	Export cmd.SubCommand `cmd:"export"`
+}
+
+// Run ...
+func (Export) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Error("You are not currently in a plot.")
+		return
+	}
+	current, err := h.DB().Plot(pos)
+	if err != nil || !mayReorganise(p, h, current) {
+		output.Error("You do not own this plot.")
+		return
+	}
+	path, err := h.DB().ExportPlot(tx, pos, current)
+	if err != nil {
+		output.Errorf("Failed exporting plot, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>The plot was exported to %v.</green>", f, f, filepath.Base(path)))
+}
@@ -0,0 +1,146 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strings"
+)
+
+// FlagSet implements the /plot flag set command. It may be used by the owner of a plot to set the value of a
+// flag on the plot.
+type FlagSet struct {
+	Flag  cmd.SubCommand `cmd:"flag"`
+	Set   cmd.SubCommand `cmd:"set"`
+	Name  flagName       `cmd:"flag"`
+	Value cmd.Varargs    `cmd:"value"`
+}
+
+// Run ...
+func (f FlagSet) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	fl, _ := plot.FlagByName(string(f.Name))
+	if err := fl.SetString(current, strings.TrimSpace(string(f.Value))); err != nil {
+		output.Errorf("Invalid value for flag %v: %v", fl.Name(), err)
+		return
+	}
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed setting flag, please try again later. (%v)", err)
+		return
+	}
+	applyFlags(tx, pos)
+
+	v, _ := fl.String(current)
+	c := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Flag %v was set to %v.</green>", c, c, fl.Name(), v))
+}
+
+// FlagUnset implements the /plot flag unset command. It may be used by the owner of a plot to remove a flag
+// from the plot.
+type FlagUnset struct {
+	Flag  cmd.SubCommand `cmd:"flag"`
+	Unset cmd.SubCommand `cmd:"unset"`
+	Name  flagName       `cmd:"flag"`
+}
+
+// Run ...
+func (f FlagUnset) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	fl, _ := plot.FlagByName(string(f.Name))
+	if _, ok := fl.String(current); !ok {
+		output.Errorf("Flag %v is not set on this plot.", fl.Name())
+		return
+	}
+	current.UnsetFlag(fl.Name())
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed removing flag, please try again later. (%v)", err)
+		return
+	}
+	applyFlags(tx, pos)
+
+	c := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Flag %v was removed.</green>", c, c, fl.Name()))
+}
+
+// FlagList implements the /plot flag list command. It lists all flags and their values on the plot that the
+// player is currently in.
+type FlagList struct {
+	Flag cmd.SubCommand `cmd:"flag"`
+	List cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (FlagList) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Error("You are not currently in a plot.")
+		return
+	}
+	current, err := h.DB().Plot(pos)
+	if err != nil {
+		output.Error("This plot is not claimed by anyone.")
+		return
+	}
+	flags := plot.RegisteredFlags()
+
+	var str strings.Builder
+	for i, fl := range flags {
+		if v, ok := fl.String(current); ok {
+			str.WriteString(text.Colourf("<white>%v:</white> <green>%v</green>", fl.Name(), v))
+		} else {
+			str.WriteString(text.Colourf("<white>%v:</white> <grey>not set</grey> <dark-grey>(%v)</dark-grey>", fl.Name(), fl.Description()))
+		}
+		if i != len(flags)-1 {
+			str.WriteString("\n")
+		}
+	}
+	c := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Flags of this plot:</green>\n", c, c) + str.String())
+}
+
+// applyFlags re-applies the flags of the plot at the plot.Position passed to all players currently in it.
+func applyFlags(tx *world.Tx, pos plot.Position) {
+	for e := range tx.Players() {
+		p := e.(*player.Player)
+		if h, ok := plot.LookupHandler(p); ok {
+			if current, ok := currentPlot(p, h); ok && current == pos {
+				h.ApplyFlags(p)
+			}
+		}
+	}
+}
+
+// flagName ...
+type flagName string
+
+// Type ...
+func (flagName) Type() string {
+	return "Flag"
+}
+
+// Options returns the names of all flags registered.
+func (flagName) Options(cmd.Source) []string {
+	flags := plot.RegisteredFlags()
+	m := make([]string, len(flags))
+	for i, fl := range flags {
+		m[i] = fl.Name()
+	}
+	return m
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+)
+
+// SetHome implements the /plot sethome command. It sets the position that players teleporting to the plot
+// are teleported to, to the current position of the owner. The owner must be standing on a block, so that
+// players do not end up in the air or inside of blocks.
+type SetHome struct {
+	SetHome cmd.SubCommand `cmd:"sethome"`
+}
+
+// Run ...
+func (SetHome) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	home := p.Position()
+	if !plot.SafeHome(tx, home) {
+		output.Errorf("You must stand on a block, outside of other blocks and liquids, to set the home of the plot.")
+		return
+	}
+	current.Home = &home
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed setting home, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Players teleporting to this plot will now arrive here.</green>", f, f))
+}
+
+// SetHomeReset implements the /plot sethome reset command. It removes the home set using /plot sethome, so
+// that players are teleported to the corner of the plot again.
+type SetHomeReset struct {
+	SetHome cmd.SubCommand `cmd:"sethome"`
+	Reset   cmd.SubCommand `cmd:"reset"`
+}
+
+// Run ...
+func (SetHomeReset) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	current.Home = nil
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed resetting home, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Players teleporting to this plot will now arrive at its corner.</green>", f, f))
+}
+
+// Middle implements the /plot middle command. It teleports the player to the middle of the plot that it is
+// currently in.
+type Middle struct {
+	Middle cmd.SubCommand `cmd:"middle"`
+}
+
+// Run ...
+func (Middle) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Error("You are not currently in a plot.")
+		return
+	}
+	p.Teleport(pos.Middle(tx, h.Settings()))
+	output.Printf(text.Colourf("<green>Successfully teleported to the middle of the plot.</green>"))
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"errors"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"reflect"
+)
+
+// importHeight is the Y at which imported structures are placed, which is the first layer above the floor
+// of a plot.
+const importHeight = 23
+
+// Import implements the /plot import command. It pastes a .mcstructure or Sponge .schem file from the imports
+// directory into the plot that the player is currently in, which it must own. The structure may be rotated
+// clockwise by 90, 180 or 270 degrees. Structures as wide as the plot, such as those created using /plot
+// export, are placed at the bottom of the plot. Other structures are placed on the floor of the plot.
+type Import struct {
+	Import   cmd.SubCommand    `cmd:"import"`
+	File     importFile        `cmd:"file"`
+	Rotation cmd.Optional[int] `cmd:"rotation"`
+}
+
+// Run ...
+func (i Import) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	if !editable(h, pos, output) {
+		return
+	}
+	rotation := i.Rotation.LoadOr(0)
+	if rotation%90 != 0 {
+		output.Errorf("Invalid rotation %v. The rotation must be 0, 90, 180 or 270.", rotation)
+		return
+	}
+	build, err := h.DB().LoadImport(string(i.File))
+	if err != nil {
+		output.Errorf("Failed reading %v. (%v)", i.File, err)
+		return
+	}
+	build, err = build.Rotate(rotation / 90)
+	if err != nil {
+		output.Errorf("Failed rotating %v. (%v)", i.File, err)
+		return
+	}
+	y, ok := pasteHeight(h, build, output)
+	if !ok {
+		return
+	}
+	previous := plot.CaptureBuild(tx, pos, h.Settings())
+	build.Paste(tx, pos, h.Settings(), y)
+	h.SetUndo("import", func(tx *world.Tx) error {
+		if pl, err := h.DB().Plot(pos); err != nil || pl.Owner != p.UUID() {
+			return errors.New("you no longer own the plot")
+		}
+		if h.DB().Locked(pos) {
+			return errors.New("the plot can no longer be edited")
+		}
+		previous.Place(tx, pos, h.Settings())
+		return nil
+	})
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Imported %v into the plot.</green>%v", f, f, i.File, undoHint(h)))
+}
+
+// pasteHeight returns the Y at which the plot.Build passed should be pasted in a plot. Builds as wide as a plot
+// are pasted at the bottom of the plot, other builds are pasted on the floor. If the build does not fit in a
+// plot, an error is written to the cmd.Output and false is returned.
+func pasteHeight(h *plot.PlayerHandler, build *plot.Build, output *cmd.Output) (int, bool) {
+	width, height, length := build.Size()
+	plotWidth := h.Settings().PlotWidth
+	if width > plotWidth || length > plotWidth {
+		output.Errorf("The structure is larger than the plot. (%vx%v, the plot is %vx%v)", width, length, plotWidth, plotWidth)
+		return 0, false
+	}
+	if width == plotWidth && length == plotWidth {
+		return 0, true
+	}
+	if importHeight+height > 256 {
+		output.Errorf("The structure is too high to fit in the plot. (%v blocks)", height)
+		return 0, false
+	}
+	return importHeight, true
+}
+
+// importFile is the name of a file in the imports directory.
+type importFile string
+
+// Type ...
+func (importFile) Type() string {
+	return "ImportFile"
+}
+
+// Parse reads any file name, so that a helpful error may be shown if the file does not exist.
+func (importFile) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	v.SetString(arg)
+	return nil
+}
+
+// Options returns the names of all files in the imports directory.
+func (importFile) Options(source cmd.Source) []string {
+	h, ok := plot.LookupHandler(source.(*player.Player))
+	if !ok {
+		return nil
+	}
+	names, _ := h.DB().Imports()
+	return names
+}
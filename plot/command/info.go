@@ -0,0 +1,102 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strings"
+	"time"
+)
+
+// Info implements the /plot info command. It shows detailed information on the plot that the player is in, or
+// on the plot with the ID passed, such as '3;-2'.
+type Info struct {
+	Info cmd.SubCommand       `cmd:"info"`
+	ID   cmd.Optional[string] `cmd:"id"`
+}
+
+// Run ...
+func (i Info) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, ok := currentPlot(p, h)
+	if id, set := i.ID.Load(); set {
+		var err error
+		if pos, err = plot.ParsePosition(id); err != nil {
+			output.Errorf("Invalid plot ID %v. Plot IDs have the format x;z, such as 3;-2.", id)
+			return
+		}
+	} else if !ok {
+		output.Error("You are not currently in a plot.")
+		return
+	}
+	min, max := pos.Bounds(h.Settings())
+	current, err := h.DB().Plot(pos)
+	if err != nil {
+		output.Printf(text.Colourf("<green>Plot %v</green> <white>(%v, %v) to (%v, %v)</white>\n<white>This plot is currently <green>free</green>.</white>", pos, min[0], min[2], max[0], max[2]))
+		return
+	}
+	f := current.ColourToFormat()
+
+	var str strings.Builder
+	str.WriteString(text.Colourf("<%v>■</%v> <green>Plot %v</green>", f, f, pos))
+	if current.Title != "" {
+		str.WriteString(text.Colourf(" <white>%v</white>", current.Title))
+	}
+	if current.Alias != "" {
+		str.WriteString(text.Colourf(" <grey>(%v)</grey>", current.Alias))
+	}
+	str.WriteString(text.Colourf("\n<white>Tags:</white> <grey>%v</grey>", listOrNone(current.Tags)))
+	str.WriteString(text.Colourf("\n<white>Bounds:</white> <grey>(%v, %v) to (%v, %v)</grey>", min[0], min[2], max[0], max[2]))
+
+	status := text.Colourf("<red>offline</red>")
+	if plot.Online(current.Owner) {
+		status = text.Colourf("<green>online</green>")
+	}
+	str.WriteString(text.Colourf("\n<white>Owner:</white> <grey>%v, plot #%v</grey> (%v)", current.OwnerName, current.Number, status))
+	str.WriteString(text.Colourf("\n<white>Helpers:</white> <grey>%v</grey>", listOrNone(playerNames(h.DB(), current.Helpers))))
+	str.WriteString(text.Colourf("\n<white>Trusted:</white> <grey>%v</grey>", listOrNone(playerNames(h.DB(), current.Trusted))))
+	str.WriteString(text.Colourf("\n<white>Claimed:</white> <grey>%v</grey>", formatTime(current.Claimed)))
+	str.WriteString(text.Colourf("\n<white>Last activity:</white> <grey>%v</grey>", formatTime(current.LastEdit)))
+	if current.Done() {
+		str.WriteString(text.Colourf("\n<white>Done:</white> <grey>%v (%v)</grey>", formatTime(current.Completed), current.Review))
+	} else if current.Review == plot.ReviewRejected {
+		str.WriteString(text.Colourf("\n<white>Review:</white> <grey>rejected: %v</grey>", current.ReviewNote))
+	}
+	str.WriteString(text.Colourf("\n<white>Popularity:</white> <grey>%v like(s), rated %.1f by %v player(s), %v visit(s)</grey>", current.Likes, current.Rating(), current.Ratings, current.Visits))
+
+	merged := make([]string, 0, len(current.MergedDirections))
+	for _, d := range current.MergedDirections {
+		merged = append(merged, d.String())
+	}
+	str.WriteString(text.Colourf("\n<white>Merged:</white> <grey>%v</grey>", listOrNone(merged)))
+
+	var flags []string
+	for _, fl := range plot.RegisteredFlags() {
+		if v, ok := fl.String(current); ok {
+			flags = append(flags, fl.Name()+"="+v)
+		}
+	}
+	str.WriteString(text.Colourf("\n<white>Flags:</white> <grey>%v</grey>", listOrNone(flags)))
+	str.WriteString(text.Colourf("\n<white>Colour:</white> <%v>%v</%v>", f, current.ColourToString(), f))
+	output.Print(str.String())
+}
+
+// listOrNone joins the strings passed with commas, or returns 'none' if the list is empty.
+func listOrNone(l []string) string {
+	if len(l) == 0 {
+		return "none"
+	}
+	return strings.Join(l, ", ")
+}
+
+// formatTime formats the time.Time passed as a date, or returns 'unknown' if it is the zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -0,0 +1,228 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"os"
+	"reflect"
+	"slices"
+)
+
+// LibWand implements the /plot lib wand command. It turns the selection wand on or off. While the wand is on,
+// breaking a block sets the first corner of the selection and using an item on a block sets the second
+// corner. The selection may then be saved in the library of the player using /plot lib save.
+type LibWand struct {
+	Lib  cmd.SubCommand `cmd:"lib"`
+	Wand cmd.SubCommand `cmd:"wand"`
+}
+
+// Run ...
+func (LibWand) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	if h.ToggleWand() {
+		output.Printf(text.Colourf("<green>Wand enabled. Break a block to set the first corner and use an item on a block to set the second corner. Use /p lib wand again to stop.</green>"))
+		return
+	}
+	output.Printf(text.Colourf("<green>Wand disabled.</green>"))
+}
+
+// LibSave implements the /plot lib save command. It saves the region selected using /plot lib wand in the
+// personal library of the player. If the player has not selected a region, the plot that the player is
+// currently in is saved instead. Either way, the blocks saved must be in a plot that the player owns.
+type LibSave struct {
+	Lib  cmd.SubCommand `cmd:"lib"`
+	Save cmd.SubCommand `cmd:"save"`
+	Name string         `cmd:"name"`
+}
+
+// Run ...
+func (l LibSave) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	if !plot.ValidLibraryName(l.Name) {
+		output.Errorf("Invalid name %v. Names may be up to 24 letters, digits, - and _ long.", l.Name)
+		return
+	}
+	names, err := h.DB().Library(p.UUID())
+	if err != nil {
+		output.Errorf("Failed reading your library, please try again later. (%v)", err)
+		return
+	}
+	if !slices.Contains(names, l.Name) && len(names) >= h.Settings().MaximumLibrary {
+		output.Errorf("Your library is full. (%v/%v) Use /p lib delete to delete a structure.", len(names), h.Settings().MaximumLibrary)
+		return
+	}
+	var build *plot.Build
+	if low, high, ok := h.Selection(); ok {
+		pos := plot.PosFromBlockPos(low, h.Settings())
+		min, max := pos.Bounds(h.Settings())
+		if !plot.Within(low, min, max) || !plot.Within(high, min, max) {
+			output.Errorf("Your selection must be within a single plot.")
+			return
+		}
+		if current, err := h.DB().Plot(pos); err != nil || current.Owner != p.UUID() {
+			output.Errorf("You may only save selections in plots that you own.")
+			return
+		}
+		build = plot.CaptureRegion(tx, low, high)
+	} else {
+		pos, _, ok := ownedPlot(p, h, output)
+		if !ok {
+			return
+		}
+		build = plot.CaptureBuild(tx, pos, h.Settings())
+	}
+	if err := h.DB().SaveToLibrary(p.UUID(), l.Name, build); errors.Is(err, plot.ErrLibraryFull) {
+		size, _ := h.DB().LibrarySize(p.UUID())
+		output.Errorf("Your library does not have enough space left for this structure. (%v/%v KiB used) Use /p lib delete to delete a structure.", size>>10, h.Settings().MaximumLibrarySize>>10)
+		return
+	} else if err != nil {
+		output.Errorf("Failed saving structure, please try again later. (%v)", err)
+		return
+	}
+	width, height, length := build.Size()
+	output.Printf(text.Colourf("<green>Saved %v (%vx%vx%v) in your library. Use /p lib paste %v to paste it.</green>", l.Name, width, height, length, l.Name))
+}
+
+// LibPaste implements the /plot lib paste command. It pastes a structure from the personal library of the
+// player into the plot that the player is currently in, which it must be able to edit. Structures as wide as
+// the plot are pasted at the height they were saved at. Other structures are pasted with their lowest corner
+// at the position of the player.
+type LibPaste struct {
+	Lib   cmd.SubCommand `cmd:"lib"`
+	Paste cmd.SubCommand `cmd:"paste"`
+	Name  libName        `cmd:"name"`
+}
+
+// Run ...
+func (l LibPaste) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Error("You are not currently in a plot.")
+		return
+	}
+	feet := cube.PosFromVec3(p.Position())
+	current, err := h.DB().Plot(pos)
+	if err != nil || !h.CanEdit(feet) {
+		output.Errorf("You cannot edit this plot.")
+		return
+	}
+	build, err := h.DB().LoadFromLibrary(p.UUID(), string(l.Name))
+	if errors.Is(err, os.ErrNotExist) {
+		output.Errorf("Unknown structure %v. Use /p lib list to see your library.", l.Name)
+		return
+	} else if err != nil {
+		output.Errorf("Failed reading structure, please try again later. (%v)", err)
+		return
+	}
+	previous := plot.CaptureBuild(tx, pos, h.Settings())
+	if width, _, length := build.Size(); width == h.Settings().PlotWidth && length == h.Settings().PlotWidth {
+		// Structures as wide as the plot are pasted at the same height as they were saved, so that a plot saved
+		// from the floor up is not pasted below the floor.
+		build.Paste(tx, pos, h.Settings(), build.Y())
+	} else {
+		build.PasteAt(tx, feet, h.Settings())
+	}
+	h.SetUndo("paste", func(tx *world.Tx) error {
+		if !h.CanEdit(feet) {
+			return errors.New("you can no longer edit the plot")
+		}
+		previous.Place(tx, pos, h.Settings())
+		return nil
+	})
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Pasted %v into the plot.</green>%v", f, f, l.Name, undoHint(h)))
+}
+
+// LibList implements the /plot lib list command. It lists all structures in the personal library of the
+// player, along with the space used by them.
+type LibList struct {
+	Lib  cmd.SubCommand `cmd:"lib"`
+	List cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (LibList) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	names, err := h.DB().Library(p.UUID())
+	if err != nil {
+		output.Errorf("Failed reading your library, please try again later. (%v)", err)
+		return
+	}
+	usage := ""
+	if limit := h.Settings().MaximumLibrarySize; limit > 0 {
+		size, err := h.DB().LibrarySize(p.UUID())
+		if err != nil {
+			output.Errorf("Failed reading your library, please try again later. (%v)", err)
+			return
+		}
+		usage = fmt.Sprintf(", %v/%v KiB", size>>10, limit>>10)
+	}
+	output.Printf(text.Colourf("<green>Library (%v/%v%v):</green> <grey>%v</grey>", len(names), h.Settings().MaximumLibrary, usage, listOrNone(names)))
+}
+
+// LibDelete implements the /plot lib delete command. It deletes a structure from the personal library of the
+// player.
+type LibDelete struct {
+	Lib    cmd.SubCommand `cmd:"lib"`
+	Delete cmd.SubCommand `cmd:"delete"`
+	Name   libName        `cmd:"name"`
+}
+
+// Run ...
+func (l LibDelete) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	if err := h.DB().RemoveFromLibrary(p.UUID(), string(l.Name)); errors.Is(err, os.ErrNotExist) {
+		output.Errorf("Unknown structure %v. Use /p lib list to see your library.", l.Name)
+		return
+	} else if err != nil {
+		output.Errorf("Failed deleting structure, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>Deleted %v from your library.</green>", l.Name))
+}
+
+// libName is the name of a structure in the library of the player.
+type libName string
+
+// Type ...
+func (libName) Type() string {
+	return "LibraryName"
+}
+
+// Parse reads any name, so that a helpful error may be shown if the structure does not exist.
+func (libName) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	v.SetString(arg)
+	return nil
+}
+
+// Options returns the names of all structures in the library of the player.
+func (libName) Options(source cmd.Source) []string {
+	p := source.(*player.Player)
+	h, ok := plot.LookupHandler(p)
+	if !ok {
+		return nil
+	}
+	names, _ := h.DB().Library(p.UUID())
+	return names
+}
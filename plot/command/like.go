@@ -0,0 +1,163 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strings"
+)
+
+// Like implements the /plot like command. It adds a like to the plot that the player is currently in. Each
+// player may like a plot only once and players cannot like their own plots.
+type Like struct {
+	Like cmd.SubCommand `cmd:"like"`
+}
+
+// Run ...
+func (Like) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := votablePlot(p, h, output)
+	if !ok {
+		return
+	}
+	if err := h.DB().Like(pos, current, p.UUID()); errors.Is(err, plot.ErrAlreadyLiked) {
+		output.Errorf("You already liked this plot.")
+		return
+	} else if err != nil {
+		output.Errorf("Failed liking plot, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>You liked %v by %v. It now has %v like(s).</green>", f, f, current.Name(), current.OwnerName, current.Likes))
+}
+
+// Rate implements the /plot rate command. It rates the plot that the player is currently in with a number of
+// stars from 1 to 5. Rating a plot again replaces the previous rating of the player.
+type Rate struct {
+	Rate   cmd.SubCommand `cmd:"rate"`
+	Rating int            `cmd:"rating"`
+}
+
+// Run ...
+func (r Rate) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	if r.Rating < 1 || r.Rating > 5 {
+		output.Errorf("The rating must be between 1 and 5.")
+		return
+	}
+	pos, current, ok := votablePlot(p, h, output)
+	if !ok {
+		return
+	}
+	if err := h.DB().Rate(pos, current, p.UUID(), r.Rating); err != nil {
+		output.Errorf("Failed rating plot, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>You rated %v by %v %v. It now has an average rating of %.1f %v.</green>", f, f, current.Name(), current.OwnerName, stars(r.Rating), current.Rating(), stars(int(current.Rating()+0.5))))
+}
+
+// votablePlot returns the plot that the player is currently in if the player may vote on it. If not, an
+// error is written to the cmd.Output and false is returned.
+func votablePlot(p *player.Player, h *plot.PlayerHandler, output *cmd.Output) (plot.Position, *plot.Plot, bool) {
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Errorf("You are not currently in a plot.")
+		return pos, nil, false
+	}
+	current, err := h.DB().Plot(pos)
+	if err != nil {
+		output.Errorf("This plot is not claimed.")
+		return pos, nil, false
+	}
+	if current.Owner == p.UUID() {
+		output.Errorf("You cannot vote on your own plot.")
+		return pos, nil, false
+	}
+	return pos, current, true
+}
+
+// stars returns a string of n stars out of 5.
+func stars(n int) string {
+	return strings.Repeat("★", n) + strings.Repeat("☆", 5-n)
+}
+
+// Top implements the /plot top command. It shows the plots with the most likes, the highest rating or the
+// most visits.
+type Top struct {
+	Top   cmd.SubCommand                `cmd:"top"`
+	Board cmd.Optional[leaderboardName] `cmd:"leaderboard"`
+	Page  cmd.Optional[int]             `cmd:"page"`
+}
+
+// topPageSize is the amount of plots shown on a single page of /plot top.
+const topPageSize = 10
+
+// Run ...
+func (t Top) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	board, page := plot.Leaderboard(t.Board.LoadOr("likes")), t.Page.LoadOr(1)
+	if page < 1 {
+		output.Errorf("Unknown page %v.", page)
+		return
+	}
+	positions, more, err := h.DB().Top(board, (page-1)*topPageSize, topPageSize)
+	if err != nil {
+		output.Errorf("Failed reading leaderboard, please try again later. (%v)", err)
+		return
+	}
+	if len(positions) == 0 {
+		output.Errorf("There are no plots on page %v of the leaderboard.", page)
+		return
+	}
+	var str strings.Builder
+	for i, pos := range positions {
+		pl, err := h.DB().Plot(pos)
+		if err != nil {
+			continue
+		}
+		var score string
+		switch board {
+		case plot.LeaderboardLikes:
+			score = fmt.Sprintf("%v like(s)", pl.Likes)
+		case plot.LeaderboardRating:
+			score = fmt.Sprintf("%.1f %v (%v)", pl.Rating(), stars(int(pl.Rating()+0.5)), pl.Ratings)
+		case plot.LeaderboardVisits:
+			score = fmt.Sprintf("%v visit(s)", pl.Visits)
+		}
+		f := pl.ColourToFormat()
+		str.WriteString(text.Colourf("\n<white>%v.</white> <%v>■</%v> <white>%v</white> <grey>by %v at %v:</grey> <yellow>%v</yellow>", (page-1)*topPageSize+i+1, f, f, pl.Name(), pl.OwnerName, pos, score))
+	}
+	if more {
+		str.WriteString(text.Colourf("\n<grey>Use /p top %v %v to see the next page.</grey>", board, page+1))
+	}
+	output.Printf(text.Colourf("<green>Top plots by %v (page %v):</green>", board, page) + str.String())
+}
+
+// leaderboardName is the name of a plot.Leaderboard.
+type leaderboardName string
+
+// Type ...
+func (leaderboardName) Type() string {
+	return "Leaderboard"
+}
+
+// Options ...
+func (leaderboardName) Options(cmd.Source) []string {
+	boards := plot.Leaderboards()
+	m := make([]string, 0, len(boards))
+	for _, l := range boards {
+		m = append(m, string(l))
+	}
+	return m
+}
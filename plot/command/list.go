@@ -1,32 +1,136 @@
 package command
 
 import (
+	"cmp"
+	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/cmd"
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/plots/plot"
+	"github.com/google/uuid"
 	"github.com/sandertv/gophertunnel/minecraft/text"
+	"slices"
 	"strings"
 )
 
-// List implements a /plot list command which may be used to check the available plots.
+// List implements a /plot list command which may be used to check the available plots. The plots listed may
+// be sorted by passing a sort order after the page.
 type List struct {
-	List cmd.SubCommand `cmd:"list"`
+	List cmd.SubCommand         `cmd:"list"`
+	Page cmd.Optional[int]      `cmd:"page"`
+	Sort cmd.Optional[listSort] `cmd:"sort"`
 }
 
 // Run ...
 func (l List) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
 	p := source.(*player.Player)
 	h, _ := plot.LookupHandler(p)
-	plots := h.Plots()
+
+	listPlots(p, h, p.UUID(), "Your plots", l.Page.LoadOr(1), l.Sort.LoadOr("number"), output)
+}
+
+// ListPlayer implements the /plot list <player> command, which lists the plots of any player, including
+// players that are currently offline.
+type ListPlayer struct {
+	List   cmd.SubCommand         `cmd:"list"`
+	Player ownerName              `cmd:"player"`
+	Page   cmd.Optional[int]      `cmd:"page"`
+	Sort   cmd.Optional[listSort] `cmd:"sort"`
+}
+
+// Run ...
+func (l ListPlayer) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	id, err := h.DB().PlayerByName(string(l.Player))
+	if err != nil {
+		output.Errorf("Unknown player %v.", l.Player)
+		return
+	}
+	name, err := h.DB().PlayerName(id)
+	if err != nil {
+		name = string(l.Player)
+	}
+	listPlots(p, h, id, "Plots of "+name, l.Page.LoadOr(1), l.Sort.LoadOr("number"), output)
+}
+
+// listPageSize is the amount of plots shown on a single page of /plot list.
+const listPageSize = 8
+
+// listPlots writes a page of the plots of the player with the UUID passed to the cmd.Output, sorted in the
+// order passed.
+func listPlots(p *player.Player, h *plot.PlayerHandler, id uuid.UUID, header string, page int, order listSort, output *cmd.Output) {
+	positions, _ := h.DB().PlayerPlots(id)
+	type entry struct {
+		pos plot.Position
+		pl  *plot.Plot
+	}
+	entries := make([]entry, 0, len(positions))
+	for _, pos := range positions {
+		if pl, err := h.DB().Plot(pos); err == nil {
+			entries = append(entries, entry{pos: pos, pl: pl})
+		}
+	}
+	if len(entries) == 0 {
+		output.Errorf("There are no plots to list.")
+		return
+	}
+	current := plot.PosFromBlockPos(cube.PosFromVec3(p.Position()), h.Settings())
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		switch order {
+		case "name":
+			return strings.Compare(strings.ToLower(a.pl.Name()), strings.ToLower(b.pl.Name()))
+		case "claimed":
+			return a.pl.Claimed.Compare(b.pl.Claimed)
+		case "distance":
+			return cmp.Compare(distanceSquared(current, a.pos), distanceSquared(current, b.pos))
+		}
+		return cmp.Compare(a.pl.Number, b.pl.Number)
+	})
+
+	pages := (len(entries) + listPageSize - 1) / listPageSize
+	if page < 1 || page > pages {
+		output.Errorf("Unknown page %v. (1-%v)", page, pages)
+		return
+	}
+	entries = entries[(page-1)*listPageSize : min(page*listPageSize, len(entries))]
 
 	var str strings.Builder
-	for i, p := range plots {
-		c := p.ColourToFormat()
-		str.WriteString(text.Colourf("<white>%v:</white> <%v>■ %v</%v>", i+1, c, p.ColourToString(), c))
-		if i != len(plots)-1 {
-			str.WriteString("\n")
+	for _, e := range entries {
+		c := e.pl.ColourToFormat()
+		str.WriteString(text.Colourf("\n<white>#%v:</white> <%v>■ %v</%v>", e.pl.Number, c, e.pl.ColourToString(), c))
+		if e.pl.Title != "" {
+			str.WriteString(text.Colourf(" <white>%v</white>", e.pl.Title))
+		}
+		if e.pl.Alias != "" {
+			str.WriteString(text.Colourf(" <grey>(%v)</grey>", e.pl.Alias))
+		}
+		str.WriteString(text.Colourf(" <grey>at %v, %v helper(s)</grey>", e.pos, len(e.pl.Helpers)))
+		for _, line := range strings.Split(e.pl.Description, "\n") {
+			if line != "" {
+				str.WriteString(text.Colourf("\n   <grey>%v</grey>", line))
+			}
 		}
 	}
-	output.Printf(text.Colourf("<green>Your plots:</green>\n" + str.String()))
+	output.Printf(text.Colourf("<green>%v (page %v/%v):</green>", header, page, pages) + str.String())
+}
+
+// distanceSquared returns the squared distance between two plot.Positions in plots.
+func distanceSquared(a, b plot.Position) int {
+	dx, dz := a[0]-b[0], a[1]-b[1]
+	return dx*dx + dz*dz
+}
+
+// listSort is the order in which plots are sorted in /plot list.
+type listSort string
+
+// Type ...
+func (listSort) Type() string {
+	return "ListSort"
+}
+
+// Options ...
+func (listSort) Options(cmd.Source) []string {
+	return []string{"number", "name", "claimed", "distance"}
 }
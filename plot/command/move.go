@@ -0,0 +1,182 @@
+package command
+
+import (
+	"errors"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strconv"
+)
+
+// Copy implements the /plot copy command. It copies all blocks in the plot that the player is currently in
+// to another plot owned by the player, replacing the blocks in that plot. Admins may copy between any plots.
+type Copy struct {
+	Copy   cmd.SubCommand `cmd:"copy"`
+	Target plotNumber     `cmd:"target"`
+}
+
+// Run ...
+func (c Copy) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	from, current, to, ok := sourceAndTarget(p, h, string(c.Target), output)
+	if !ok {
+		return
+	}
+	target, err := h.DB().Plot(to)
+	if err != nil && !h.Settings().Admin(p.UUID()) {
+		output.Errorf("You can only copy to plots that you own. Use /p move to move the plot to a free plot.")
+		return
+	} else if err == nil && !mayReorganise(p, h, target) {
+		output.Errorf("You can only copy to plots that you own.")
+		return
+	} else if err == nil && h.DB().Locked(to) {
+		output.Errorf("The plot %v cannot be edited.", to)
+		return
+	}
+	previous := plot.CaptureBuild(tx, to, h.Settings())
+	plot.CaptureBuild(tx, from, h.Settings()).Place(tx, to, h.Settings())
+	h.SetUndo("copy", func(tx *world.Tx) error {
+		if target, err := h.DB().Plot(to); (err != nil && !h.Settings().Admin(p.UUID())) || (err == nil && !mayReorganise(p, h, target)) {
+			return errors.New("you can no longer edit the plot")
+		}
+		if h.DB().Locked(to) {
+			return errors.New("the plot can no longer be edited")
+		}
+		previous.Place(tx, to, h.Settings())
+		return nil
+	})
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Copied the plot to %v.</green>%v", f, f, to, undoHint(h)))
+}
+
+// Move implements the /plot move command. It moves the plot that the player is currently in, including its
+// blocks, helpers, flags, comments and snapshots, to a free plot. Admins may move any plot.
+type Move struct {
+	Move   cmd.SubCommand `cmd:"move"`
+	Target plotNumber     `cmd:"target"`
+}
+
+// Run ...
+func (m Move) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	from, current, to, ok := sourceAndTarget(p, h, string(m.Target), output)
+	if !ok || !movable(h, from, current, output) {
+		return
+	}
+	if target, err := h.DB().Plot(to); err == nil {
+		output.Errorf("The plot %v is already claimed by %v. Use /p swap to swap the plots.", to, target.OwnerName)
+		return
+	}
+	if !h.Settings().Claimable(to) {
+		output.Errorf("The plot %v is reserved and cannot be claimed.", to)
+		return
+	}
+	if err := h.DB().MovePlot(tx, from, to); err != nil {
+		output.Errorf("Failed moving plot, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Moved the plot to %v.</green>", f, f, to))
+}
+
+// Swap implements the /plot swap command. It swaps the plot that the player is currently in with another plot
+// owned by the player, including their blocks, helpers, flags, comments and snapshots. Admins may swap any
+// plots.
+type Swap struct {
+	Swap   cmd.SubCommand `cmd:"swap"`
+	Target plotNumber     `cmd:"target"`
+}
+
+// Run ...
+func (s Swap) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	from, current, to, ok := sourceAndTarget(p, h, string(s.Target), output)
+	if !ok || !movable(h, from, current, output) {
+		return
+	}
+	target, err := h.DB().Plot(to)
+	if err != nil {
+		output.Errorf("The plot %v is not claimed. Use /p move to move the plot to it.", to)
+		return
+	}
+	if !mayReorganise(p, h, target) {
+		output.Errorf("You can only swap plots that you own.")
+		return
+	}
+	if !movable(h, to, target, output) {
+		return
+	}
+	if err := h.DB().SwapPlots(tx, from, to); err != nil {
+		output.Errorf("Failed swapping plots, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Swapped the plot with %v.</green>", f, f, to))
+}
+
+// sourceAndTarget returns the plot that the player is currently in, which it must own unless it is an admin,
+// and the position of the target plot passed as a plot number, alias or ID. If either of the plots is not
+// valid, an error is written to the cmd.Output and false is returned.
+func sourceAndTarget(p *player.Player, h *plot.PlayerHandler, target string, output *cmd.Output) (plot.Position, *plot.Plot, plot.Position, bool) {
+	from, ok := currentPlot(p, h)
+	if !ok {
+		output.Error("You are not currently in a plot.")
+		return from, nil, from, false
+	}
+	current, err := h.DB().Plot(from)
+	if err != nil || !mayReorganise(p, h, current) {
+		output.Error("You do not own this plot.")
+		return from, nil, from, false
+	}
+	to, ok := resolvePlot(p, h, target)
+	if !ok {
+		output.Errorf("Unknown plot %v. Use a plot number, alias or ID such as 3;-2.", target)
+		return from, nil, from, false
+	}
+	if to == from {
+		output.Errorf("The target plot must be a different plot.")
+		return from, nil, from, false
+	}
+	return from, current, to, true
+}
+
+// mayReorganise checks if the player passed may copy, move or swap the plot.Plot passed.
+func mayReorganise(p *player.Player, h *plot.PlayerHandler, pl *plot.Plot) bool {
+	return pl.Owner == p.UUID() || h.Settings().Admin(p.UUID())
+}
+
+// movable checks if the plot.Plot at the plot.Position passed may be moved. Merged plots and plots entered in
+// a contest cannot be moved. If not, an error is written to the cmd.Output and false is returned.
+func movable(h *plot.PlayerHandler, pos plot.Position, pl *plot.Plot, output *cmd.Output) bool {
+	if len(pl.MergedDirections) > 0 {
+		output.Errorf("The plot %v is merged with other plots and cannot be moved.", pos)
+		return false
+	}
+	if h.DB().ContestLocked(pos) {
+		output.Errorf("The plot %v is entered in a running contest and cannot be moved.", pos)
+		return false
+	}
+	return true
+}
+
+// resolvePlot resolves a plot number of the player passed, an alias or an ID such as '3;-2' to the
+// plot.Position of the plot. False is returned if no such plot exists.
+func resolvePlot(p *player.Player, h *plot.PlayerHandler, s string) (plot.Position, bool) {
+	if number, err := strconv.Atoi(s); err == nil {
+		pos, _, err := h.DB().PlotByNumber(p.UUID(), number)
+		return pos, err == nil
+	}
+	if pos, err := plot.ParsePosition(s); err == nil {
+		return pos, true
+	}
+	pos, err := h.DB().PlotByAlias(s)
+	return pos, err == nil
+}
@@ -0,0 +1,148 @@
+package command
+
+import (
+	"errors"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"regexp"
+	"strings"
+)
+
+// Alias implements the /plot alias command. It may be used by the owner of a plot to give the plot an alias
+// that is unique across the server, which other players may use to teleport to the plot.
+type Alias struct {
+	Alias cmd.SubCommand `cmd:"alias"`
+	Name  string         `cmd:"alias"`
+}
+
+// aliasRegex is a regular expression that valid aliases must match. Aliases must start with a letter so that
+// they cannot be confused with plot numbers or coordinates.
+var aliasRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{2,15}$`)
+
+// Run ...
+func (a Alias) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	if !aliasRegex.MatchString(a.Name) || strings.EqualFold(a.Name, "clear") {
+		output.Error("Aliases must be 3-16 characters long, start with a letter and only contain letters, numbers, '_' and '-'.")
+		return
+	}
+	if err := h.DB().SetAlias(pos, current, a.Name); err != nil {
+		if errors.Is(err, plot.ErrAliasTaken) {
+			output.Errorf("The alias %v is already used by another plot.", a.Name)
+			return
+		}
+		output.Errorf("Failed setting alias, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>The alias of this plot is now %v.</green>", f, f, a.Name))
+}
+
+// AliasClear implements the /plot alias clear command. It removes the alias of the plot that the player is
+// in.
+type AliasClear struct {
+	Alias cmd.SubCommand `cmd:"alias"`
+	Clear cmd.SubCommand `cmd:"clear"`
+}
+
+// Run ...
+func (AliasClear) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	if err := h.DB().SetAlias(pos, current, ""); err != nil {
+		output.Errorf("Failed removing alias, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>The alias of this plot was removed.</green>", f, f))
+}
+
+// Title implements the /plot title command. It sets the title of the plot that the player is in, which is
+// shown to players entering the plot. Running the command without a title removes the title.
+type Title struct {
+	Title cmd.SubCommand            `cmd:"title"`
+	Text  cmd.Optional[cmd.Varargs] `cmd:"title"`
+}
+
+// Run ...
+func (t Title) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	title := strings.TrimSpace(string(t.Text.LoadOr("")))
+	if len(title) > 32 {
+		output.Error("Titles may be at most 32 characters long.")
+		return
+	}
+	current.Title = title
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed setting title, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	if title == "" {
+		output.Printf(text.Colourf("<%v>■</%v> <green>The title of this plot was removed.</green>", f, f))
+		return
+	}
+	output.Printf(text.Colourf("<%v>■</%v> <green>The title of this plot is now %v.</green>", f, f, title))
+}
+
+// Description implements the /plot description command. It sets the description of the plot that the player
+// is in, which is shown to players entering the plot. New lines may be started using '\n'. Running the
+// command without a description removes the description.
+type Description struct {
+	Description cmd.SubCommand            `cmd:"description"`
+	Text        cmd.Optional[cmd.Varargs] `cmd:"description"`
+}
+
+// Run ...
+func (d Description) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	lines := strings.Split(string(d.Text.LoadOr("")), `\n`)
+	if len(lines) > 3 {
+		output.Error("Descriptions may be at most 3 lines long.")
+		return
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+		if len(lines[i]) > 64 {
+			output.Error("Lines of descriptions may be at most 64 characters long.")
+			return
+		}
+	}
+	current.Description = strings.TrimSpace(strings.Join(lines, "\n"))
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed setting description, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	if current.Description == "" {
+		output.Printf(text.Colourf("<%v>■</%v> <green>The description of this plot was removed.</green>", f, f))
+		return
+	}
+	output.Printf(text.Colourf("<%v>■</%v> <green>The description of this plot was changed.</green>", f, f))
+}
@@ -0,0 +1,131 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strings"
+)
+
+// Permission implements the /plot perm command. It may be used by the owner of a plot to allow or disallow
+// specific actions for all players with a role on the plot.
+type Permission struct {
+	Perm       cmd.SubCommand  `cmd:"perm"`
+	Role       roleName        `cmd:"role"`
+	Permission permissionName  `cmd:"permission"`
+	Value      permissionValue `cmd:"value"`
+}
+
+// Run ...
+func (perm Permission) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	r, _ := plot.RoleByName(string(perm.Role))
+	pm, _ := plot.PermissionByName(string(perm.Permission))
+	allowed, verb := perm.Value == "allow", "now"
+	if !allowed {
+		verb = "no longer"
+	}
+	current.SetPermission(r, pm, allowed)
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed changing permission, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Players with the role %v %v have the %v permission.</green>", f, f, r, verb, pm))
+}
+
+// PermissionList implements the /plot perm list command. It shows the permissions of every role on the plot
+// that the player is currently in.
+type PermissionList struct {
+	Perm cmd.SubCommand `cmd:"perm"`
+	List cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (PermissionList) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Error("You are not currently in a plot.")
+		return
+	}
+	current, err := h.DB().Plot(pos)
+	if err != nil {
+		output.Error("This plot is not claimed by anyone.")
+		return
+	}
+	var str strings.Builder
+	for i, r := range plot.Roles() {
+		perms := current.RolePermissions(r)
+		str.WriteString(text.Colourf("<white>%v:</white>", r))
+		for _, pm := range plot.Permissions() {
+			if perms&pm != 0 {
+				str.WriteString(text.Colourf(" <green>%v</green>", pm))
+			} else {
+				str.WriteString(text.Colourf(" <red>%v</red>", pm))
+			}
+		}
+		if i != len(plot.Roles())-1 {
+			str.WriteString("\n")
+		}
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Permissions of this plot:</green>\n", f, f) + str.String())
+}
+
+// roleName ...
+type roleName string
+
+// Type ...
+func (roleName) Type() string {
+	return "Role"
+}
+
+// Options returns the names of all roles that may have their permissions changed.
+func (roleName) Options(cmd.Source) []string {
+	m := make([]string, 0, len(plot.Roles()))
+	for _, r := range plot.Roles() {
+		m = append(m, r.String())
+	}
+	return m
+}
+
+// permissionName ...
+type permissionName string
+
+// Type ...
+func (permissionName) Type() string {
+	return "Permission"
+}
+
+// Options returns the names of all permissions.
+func (permissionName) Options(cmd.Source) []string {
+	m := make([]string, 0, len(plot.Permissions()))
+	for _, pm := range plot.Permissions() {
+		m = append(m, pm.String())
+	}
+	return m
+}
+
+// permissionValue ...
+type permissionValue string
+
+// Type ...
+func (permissionValue) Type() string {
+	return "PermissionValue"
+}
+
+// Options ...
+func (permissionValue) Options(cmd.Source) []string {
+	return []string{"allow", "deny"}
+}
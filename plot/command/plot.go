@@ -0,0 +1,86 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/google/uuid"
+)
+
+// currentPlot returns the plot.Position of the plot that the player.Player passed is currently standing in.
+// False is returned if the player is not standing within the bounds of any plot.
+func currentPlot(p *player.Player, h *plot.PlayerHandler) (plot.Position, bool) {
+	blockPos := cube.PosFromVec3(p.Position())
+	pos := plot.PosFromBlockPos(blockPos, h.Settings())
+
+	min, max := pos.Bounds(h.Settings())
+	return pos, plot.Within(blockPos, min, max)
+}
+
+// ownedPlot returns the plot.Position and plot.Plot that the player.Player passed is currently standing in, if
+// the player owns it. If not, an error message is written to the cmd.Output and false is returned.
+func ownedPlot(p *player.Player, h *plot.PlayerHandler, output *cmd.Output) (plot.Position, *plot.Plot, bool) {
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Error("You are not currently in a plot.")
+		return pos, nil, false
+	}
+	current, err := h.DB().Plot(pos)
+	if err != nil || current.Owner != p.UUID() {
+		output.Error("You do not own this plot.")
+		return pos, nil, false
+	}
+	return pos, current, true
+}
+
+// editable checks if the blocks of the plot at the plot.Position passed may be changed. Plots that are done or
+// that were submitted to a contest that is open for submissions may not be changed. If the plot may not be
+// changed, an error is written to the cmd.Output and false is returned.
+func editable(h *plot.PlayerHandler, pos plot.Position, output *cmd.Output) bool {
+	if h.DB().Locked(pos) {
+		output.Errorf("This plot is done or was submitted to a contest and cannot be edited.")
+		return false
+	}
+	return true
+}
+
+// playerNames returns the names of the players with the UUIDs passed, as last recorded in the plot.DB. If the
+// name of a player is unknown, its UUID is used instead.
+func playerNames(db *plot.DB, ids []uuid.UUID) []string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		name, err := db.PlayerName(id)
+		if err != nil {
+			name = id.String()
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// onlinePlayer looks up the player.Player with the UUID passed in the world.Tx. False is returned if the
+// player is not online in the world.
+func onlinePlayer(tx *world.Tx, id uuid.UUID) (*player.Player, bool) {
+	for e := range tx.Players() {
+		if p := e.(*player.Player); p.UUID() == id {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// adminOnly may be embedded in a command to only allow admins, as specified in plot.Settings.Admins, to run
+// it.
+type adminOnly struct{}
+
+// Allow ...
+func (adminOnly) Allow(source cmd.Source) bool {
+	p, ok := source.(*player.Player)
+	if !ok {
+		return false
+	}
+	h, ok := plot.LookupHandler(p)
+	return ok && h.Settings().Admin(p.UUID())
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"math/rand/v2"
+	"slices"
+)
+
+// Random implements the /plot random command. It teleports the player to a random claimed plot. Filters such
+// as 'owner:Steve' may be passed to only select plots that match them.
+type Random struct {
+	Random cmd.SubCommand            `cmd:"random"`
+	Filter cmd.Optional[cmd.Varargs] `cmd:"filter"`
+}
+
+// Run ...
+func (r Random) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	filters, err := plot.ParseFilters(string(r.Filter.LoadOr("")))
+	if err != nil {
+		output.Errorf("%v", err)
+		return
+	}
+	positions, ok := visitablePlots(p, h, filters, output)
+	if !ok {
+		return
+	}
+	if current, ok := currentPlot(p, h); ok && len(positions) > 1 {
+		// Prevent 'teleporting' to the plot that the player is already in.
+		positions = slices.DeleteFunc(positions, func(pos plot.Position) bool { return pos == current })
+	}
+	teleportToPlot(p, h, positions[rand.IntN(len(positions))], output, tx)
+}
+
+// Next implements the /plot next command. It teleports the player to the next claimed plot, so that players
+// may tour all plots by running the command repeatedly. Plots are ordered by their distance to the spawn.
+type Next struct {
+	Next cmd.SubCommand `cmd:"next"`
+}
+
+// Run ...
+func (Next) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	tour(source.(*player.Player), true, output, tx)
+}
+
+// Prev implements the /plot prev command. It is the opposite of /plot next, teleporting the player to the
+// previous claimed plot.
+type Prev struct {
+	Prev cmd.SubCommand `cmd:"prev"`
+}
+
+// Run ...
+func (Prev) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	tour(source.(*player.Player), false, output, tx)
+}
+
+// tour teleports the player to the claimed plot after or, if forward is false, before the plot that the player
+// is currently in. Plots are ordered in a spiral around the spawn plot, which keeps the order stable as plots
+// are claimed and deleted. Players that are not in a plot start at the beginning or end of the tour.
+func tour(p *player.Player, forward bool, output *cmd.Output, tx *world.Tx) {
+	h, _ := plot.LookupHandler(p)
+
+	positions, ok := visitablePlots(p, h, nil, output)
+	if !ok {
+		return
+	}
+	centre := plot.PosFromBlockPos(tx.World().Spawn(), h.Settings())
+	plot.SortSpiral(positions, centre)
+
+	next := positions[0]
+	if !forward {
+		next = positions[len(positions)-1]
+	}
+	if current, ok := currentPlot(p, h); ok {
+		n := current.SpiralIndex(centre)
+		if forward {
+			if i := slices.IndexFunc(positions, func(pos plot.Position) bool { return pos.SpiralIndex(centre) > n }); i != -1 {
+				next = positions[i]
+			}
+		} else {
+			for _, pos := range positions {
+				if pos.SpiralIndex(centre) >= n {
+					break
+				}
+				next = pos
+			}
+		}
+	}
+	teleportToPlot(p, h, next, output, tx)
+}
+
+// visitablePlots returns the positions of all claimed plots that match the filters passed and that the
+// player is not denied from. If there are no such plots, an error is written to the cmd.Output and false is
+// returned.
+func visitablePlots(p *player.Player, h *plot.PlayerHandler, filters []plot.Filter, output *cmd.Output) ([]plot.Position, bool) {
+	filters = append(filters, plot.FilterFunc(func(_ plot.Position, pl *plot.Plot) bool {
+		return !pl.Denies(p.UUID())
+	}))
+	positions, err := h.DB().FindPlots(filters)
+	if err != nil {
+		output.Errorf("Failed finding plots, please try again later. (%v)", err)
+		return nil, false
+	}
+	if len(positions) == 0 {
+		output.Errorf("No plots could be found.")
+		return nil, false
+	}
+	return positions, true
+}
+
+// teleportToPlot teleports the player to the claimed plot at the plot.Position passed.
+func teleportToPlot(p *player.Player, h *plot.PlayerHandler, pos plot.Position, output *cmd.Output, tx *world.Tx) {
+	pl, err := h.DB().Plot(pos)
+	if err != nil {
+		output.Errorf("Failed reading plot, please try again later. (%v)", err)
+		return
+	}
+	p.Teleport(pl.SpawnPosition(tx, pos, h.Settings()))
+
+	f := pl.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Teleported to %v by %v. (%v)</green>", f, f, pl.Name(), pl.OwnerName, pos))
+}
@@ -0,0 +1,159 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strings"
+	"time"
+)
+
+// Done implements the /plot done command. It marks the plot that the player is currently in, which it must
+// own, as done. Nobody, including the owner and its helpers, can edit the plot afterwards. The plot is added
+// to the review queue of staff.
+type Done struct {
+	Done cmd.SubCommand `cmd:"done"`
+}
+
+// Run ...
+func (Done) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	if current.Done() {
+		output.Errorf("This plot is already done. (%v)", current.Review)
+		return
+	}
+	if err := h.DB().Complete(pos, current); err != nil {
+		output.Errorf("Failed marking plot as done, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>The plot was marked as done and can no longer be edited. It will be reviewed by staff soon.</green>", f, f))
+}
+
+// ReviewNext implements the /plot review next command. It teleports the staff member to the plot that has been
+// waiting in the review queue for the longest time. Only admins may review plots.
+type ReviewNext struct {
+	adminOnly
+	Review cmd.SubCommand `cmd:"review"`
+	Next   cmd.SubCommand `cmd:"next"`
+}
+
+// Run ...
+func (ReviewNext) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, ok, err := h.DB().NextReview()
+	if err != nil {
+		output.Errorf("Failed reading review queue, please try again later. (%v)", err)
+		return
+	} else if !ok {
+		output.Errorf("There are no plots waiting for review.")
+		return
+	}
+	pl, err := h.DB().Plot(pos)
+	if err != nil {
+		output.Errorf("Failed reading plot, please try again later. (%v)", err)
+		return
+	}
+	p.Teleport(pl.SpawnPosition(tx, pos, h.Settings()))
+
+	f := pl.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Reviewing %v by %v, done at %v. %v plot(s) are waiting for review. Use /p review approve or /p review reject <reason>.</green>", f, f, pl.Name(), pl.OwnerName, formatTime(pl.Completed), h.DB().ReviewQueueLength()))
+}
+
+// ReviewApprove implements the /plot review approve command. It approves the plot that the staff member is
+// currently in, which may allow the owner to claim more plots.
+type ReviewApprove struct {
+	adminOnly
+	Review  cmd.SubCommand            `cmd:"review"`
+	Approve cmd.SubCommand            `cmd:"approve"`
+	Note    cmd.Optional[cmd.Varargs] `cmd:"note"`
+}
+
+// Run ...
+func (r ReviewApprove) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := reviewedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	note := strings.TrimSpace(string(r.Note.LoadOr("")))
+	if err := h.DB().Approve(pos, current, note); err != nil {
+		output.Errorf("Failed approving plot, please try again later. (%v)", err)
+		return
+	}
+	msg := "Your plot " + current.Name() + " was approved."
+	if note != "" {
+		msg += " " + note
+	}
+	notifyOwner(p, h, pos, current, msg, tx)
+
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Approved %v by %v.</green>", f, f, current.Name(), current.OwnerName))
+}
+
+// ReviewReject implements the /plot review reject command. It rejects the plot that the staff member is
+// currently in for a reason, after which the owner may edit the plot again.
+type ReviewReject struct {
+	adminOnly
+	Review cmd.SubCommand `cmd:"review"`
+	Reject cmd.SubCommand `cmd:"reject"`
+	Reason cmd.Varargs    `cmd:"reason"`
+}
+
+// Run ...
+func (r ReviewReject) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := reviewedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	reason := strings.TrimSpace(string(r.Reason))
+	if err := h.DB().Reject(pos, current, reason); err != nil {
+		output.Errorf("Failed rejecting plot, please try again later. (%v)", err)
+		return
+	}
+	notifyOwner(p, h, pos, current, "Your plot "+current.Name()+" was rejected and may be edited again: "+reason, tx)
+
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Rejected %v by %v.</green>", f, f, current.Name(), current.OwnerName))
+}
+
+// reviewedPlot returns the plot that the player is currently in if it is pending review. If not, an error is
+// written to the cmd.Output and false is returned.
+func reviewedPlot(p *player.Player, h *plot.PlayerHandler, output *cmd.Output) (plot.Position, *plot.Plot, bool) {
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Errorf("You are not currently in a plot. Use /p review next to go to the next plot to review.")
+		return pos, nil, false
+	}
+	current, err := h.DB().Plot(pos)
+	if err != nil || current.Review != plot.ReviewPending {
+		output.Errorf("This plot is not waiting for review. Use /p review next to go to the next plot to review.")
+		return pos, nil, false
+	}
+	return pos, current, true
+}
+
+// notifyOwner notifies the owner of the plot passed of the result of a review. The message is left in the
+// inbox of the owner, so that it is also delivered if the owner is offline.
+func notifyOwner(p *player.Player, h *plot.PlayerHandler, pos plot.Position, pl *plot.Plot, msg string, tx *world.Tx) {
+	_ = h.DB().AddComment(pos, plot.Comment{Author: p.UUID(), AuthorName: p.Name(), Text: msg, Time: time.Now()})
+	if owner, ok := onlinePlayer(tx, pl.Owner); ok {
+		f := pl.ColourToFormat()
+		owner.Message(text.Colourf("<%v>■</%v> <yellow>%v</yellow>", f, f, msg))
+	}
+}
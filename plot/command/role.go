@@ -0,0 +1,49 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+)
+
+// Role implements the /plot role command. It may be used by the owner of a plot to make another player a
+// helper or trusted player on the plot, or to turn it back into a visitor.
+type Role struct {
+	Role    cmd.SubCommand `cmd:"role"`
+	Targets []cmd.Target   `cmd:"player"`
+	Name    roleName       `cmd:"role"`
+}
+
+// Run ...
+func (r Role) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	if len(r.Targets) != 1 {
+		output.Error("Please specify exactly one player.")
+		return
+	}
+	target, ok := r.Targets[0].(*player.Player)
+	if !ok {
+		output.Error("Roles can only be given to players.")
+		return
+	}
+	if target.UUID() == p.UUID() {
+		output.Error("You cannot change your own role on your plot.")
+		return
+	}
+	role, _ := plot.RoleByName(string(r.Name))
+	current.SetRole(target.UUID(), role)
+	if err := h.DB().StorePlot(pos, current); err != nil {
+		output.Errorf("Failed changing role, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>%v now has the role %v on this plot.</green>", f, f, target.Name(), role))
+}
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"reflect"
+	"time"
+)
+
+// Inspect implements the /plot inspect command. It turns inspecting blocks on or off. While inspecting,
+// clicking a block in a plot shows who changed it and when, instead of interacting with it.
+type Inspect struct {
+	Inspect cmd.SubCommand `cmd:"inspect"`
+}
+
+// Run ...
+func (Inspect) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	if h.ToggleInspect() {
+		output.Printf(text.Colourf("<green>Inspecting enabled. Click a block to see its history. Use /p inspect again to stop.</green>"))
+		return
+	}
+	output.Printf(text.Colourf("<green>Inspecting disabled.</green>"))
+}
+
+// Rollback implements the /plot rollback command. It reverts all changes that a single player made in the
+// plot that the player is currently in, which it must own, within a duration such as '2h' or '3d'. Blocks
+// changed by other players afterwards are left untouched.
+type Rollback struct {
+	Rollback cmd.SubCommand `cmd:"rollback"`
+	Player   memberName     `cmd:"player"`
+	Duration string         `cmd:"duration"`
+}
+
+// Run ...
+func (r Rollback) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok || !editable(h, pos, output) {
+		return
+	}
+	d, err := parseDuration(r.Duration)
+	if err != nil {
+		output.Errorf("Invalid duration %v, use for example 30m, 2h or 3d. (%v)", r.Duration, err)
+		return
+	}
+	id, err := h.DB().PlayerByName(string(r.Player))
+	if err != nil {
+		output.Errorf("Unknown player %v.", r.Player)
+		return
+	}
+	n, err := h.DB().Rollback(tx, pos, id, time.Now().Add(-d), p.UUID())
+	if err != nil {
+		output.Errorf("Failed rolling back changes, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Reverted %v block(s) changed by %v in the last %v.</green>", f, f, n, r.Player, r.Duration))
+}
+
+// memberName is the name of a player, typically a helper or trusted player of the plot that the player is
+// currently in.
+type memberName string
+
+// Type ...
+func (memberName) Type() string {
+	return "PlotMember"
+}
+
+// Parse reads the name of any player, as players that are no longer helpers may still have changed blocks in
+// the plot.
+func (memberName) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	v.SetString(arg)
+	return nil
+}
+
+// Options returns the names of the helpers and trusted players of the plot that the player is currently in.
+func (memberName) Options(source cmd.Source) []string {
+	p := source.(*player.Player)
+	h, ok := plot.LookupHandler(p)
+	if !ok {
+		return nil
+	}
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		return nil
+	}
+	if pl, err := h.DB().Plot(pos); err == nil {
+		return append(playerNames(h.DB(), pl.Helpers), playerNames(h.DB(), pl.Trusted)...)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"slices"
+	"strings"
+)
+
+// Search implements the /plot search command. It searches for plots matching filters such as 'tag:medieval',
+// 'owner:Steve' and 'likes>5'. The plots found may be visited using /plot visit <number>.
+type Search struct {
+	Search cmd.SubCommand `cmd:"search"`
+	Filter cmd.Varargs    `cmd:"filter"`
+}
+
+// searchResultsShown is the maximum amount of results shown by /plot search.
+const searchResultsShown = 10
+
+// Run ...
+func (s Search) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	filters, err := plot.ParseFilters(string(s.Filter))
+	if err != nil {
+		output.Errorf("%v. Available filters: %v.", err, strings.Join(plot.FilterKeys(), ", "))
+		return
+	}
+	positions, ok := visitablePlots(p, h, filters, output)
+	if !ok {
+		return
+	}
+	plots := make(map[plot.Position]*plot.Plot, len(positions))
+	for _, pos := range positions {
+		plots[pos], _ = h.DB().Plot(pos)
+	}
+	// Show the most liked plots first.
+	slices.SortFunc(positions, func(a, b plot.Position) int {
+		return cmp.Or(cmp.Compare(plots[b].Likes, plots[a].Likes), cmp.Compare(a.SpiralIndex(plot.Position{}), b.SpiralIndex(plot.Position{})))
+	})
+	h.SetSearchResults(positions)
+
+	var str strings.Builder
+	for i, pos := range positions[:min(len(positions), searchResultsShown)] {
+		pl := plots[pos]
+		f := pl.ColourToFormat()
+		details := fmt.Sprintf("by %v at %v, %v like(s)", pl.OwnerName, pos, pl.Likes)
+		if len(pl.Tags) != 0 {
+			details += ", " + strings.Join(pl.Tags, ", ")
+		}
+		str.WriteString(text.Colourf("\n<white>%v:</white> <%v>■</%v> <white>%v</white> <grey>%v</grey>", i+1, f, f, pl.Name(), details))
+	}
+	if len(positions) > searchResultsShown {
+		str.WriteString(text.Colourf("\n<grey>...and %v more.</grey>", len(positions)-searchResultsShown))
+	}
+	output.Printf(text.Colourf("<green>Found %v plot(s). Use /p visit <number> to visit one of them.</green>", len(positions)) + str.String())
+}
@@ -0,0 +1,199 @@
+package command
+
+import (
+	"errors"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SnapshotSave implements the /plot snapshot save command. It saves a copy of the blocks in the plot that the
+// player is currently in, which it must own, so that the plot may be rolled back to it later. If no name is
+// passed, the snapshot is named after the current time.
+type SnapshotSave struct {
+	Snapshot cmd.SubCommand       `cmd:"snapshot"`
+	Save     cmd.SubCommand       `cmd:"save"`
+	Name     cmd.Optional[string] `cmd:"name"`
+}
+
+// Run ...
+func (s SnapshotSave) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	name := s.Name.LoadOr(time.Now().Format("2006-01-02-1504"))
+	if !plot.ValidSnapshotName(name) {
+		output.Errorf("Invalid snapshot name %v. Names may be up to 24 letters, digits, - and _ long and may not start with auto-.", name)
+		return
+	}
+	if _, err := h.DB().Snapshot(pos, name); errors.Is(err, leveldb.ErrNotFound) {
+		n, err := h.DB().SnapshotCount(h.PlotPositions())
+		if err != nil {
+			output.Errorf("Failed saving snapshot, please try again later. (%v)", err)
+			return
+		}
+		if n >= h.Settings().MaximumSnapshots {
+			output.Errorf("You have reached the maximum amount of snapshots. (%v/%v) Use /p snapshot delete to delete one.", n, h.Settings().MaximumSnapshots)
+			return
+		}
+	}
+	if _, err := h.DB().SaveSnapshot(tx, pos, name, p.Name()); err != nil {
+		output.Errorf("Failed saving snapshot, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Saved snapshot %v. Use /p snapshot restore %v to roll the plot back to it.</green>", f, f, name, name))
+}
+
+// SnapshotList implements the /plot snapshot list command. It lists all snapshots of the plot that the player
+// is currently in, which it must own.
+type SnapshotList struct {
+	Snapshot cmd.SubCommand `cmd:"snapshot"`
+	List     cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (SnapshotList) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	snapshots, err := h.DB().Snapshots(pos)
+	if err != nil {
+		output.Errorf("Failed reading snapshots, please try again later. (%v)", err)
+		return
+	}
+	if len(snapshots) == 0 {
+		output.Errorf("This plot does not have any snapshots. Use /p snapshot save to save one.")
+		return
+	}
+	var str strings.Builder
+	for _, s := range snapshots {
+		author := "automatic"
+		if !s.Auto {
+			author = "by " + s.AuthorName
+		}
+		str.WriteString(text.Colourf("\n<white>%v</white> <grey>(%v, %v)</grey>", s.Name, formatTime(s.Created), author))
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Snapshots of %v:</green>", f, f, current.Name()) + str.String())
+}
+
+// SnapshotRestore implements the /plot snapshot restore command. It rolls the plot that the player is
+// currently in, which it must own, back to one of its snapshots. Plots that are done or submitted to a contest
+// cannot be rolled back.
+type SnapshotRestore struct {
+	Snapshot cmd.SubCommand `cmd:"snapshot"`
+	Restore  cmd.SubCommand `cmd:"restore"`
+	Name     snapshotName   `cmd:"name"`
+}
+
+// Run ...
+func (s SnapshotRestore) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	snapshot, current, ok := readSnapshot(p, h, string(s.Name), output)
+	if !ok || !editable(h, snapshot.Pos, output) {
+		return
+	}
+	if err := h.DB().RestoreSnapshot(tx, snapshot); err != nil {
+		output.Errorf("Failed restoring snapshot, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>The plot was rolled back to snapshot %v of %v.</green>", f, f, snapshot.Name, formatTime(snapshot.Created)))
+}
+
+// SnapshotDelete implements the /plot snapshot delete command. It deletes one of the snapshots of the plot
+// that the player is currently in, which it must own.
+type SnapshotDelete struct {
+	Snapshot cmd.SubCommand `cmd:"snapshot"`
+	Delete   cmd.SubCommand `cmd:"delete"`
+	Name     snapshotName   `cmd:"name"`
+}
+
+// Run ...
+func (s SnapshotDelete) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	snapshot, current, ok := readSnapshot(p, h, string(s.Name), output)
+	if !ok {
+		return
+	}
+	if err := h.DB().RemoveSnapshot(snapshot); err != nil {
+		output.Errorf("Failed deleting snapshot, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Snapshot %v was deleted.</green>", f, f, snapshot.Name))
+}
+
+// readSnapshot reads the snapshot with the name passed of the plot that the player is currently in, which it
+// must own. If the snapshot could not be found, an error is written to the cmd.Output and false is returned.
+func readSnapshot(p *player.Player, h *plot.PlayerHandler, name string, output *cmd.Output) (*plot.Snapshot, *plot.Plot, bool) {
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return nil, nil, false
+	}
+	snapshot, err := h.DB().Snapshot(pos, name)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		output.Errorf("This plot does not have a snapshot named %v.", name)
+		return nil, nil, false
+	} else if err != nil {
+		output.Errorf("Failed reading snapshot, please try again later. (%v)", err)
+		return nil, nil, false
+	}
+	return snapshot, current, true
+}
+
+// snapshotName is the name of a snapshot of the plot that the player is currently in.
+type snapshotName string
+
+// Type ...
+func (snapshotName) Type() string {
+	return "SnapshotName"
+}
+
+// Parse reads any name, so that a helpful error may be shown if the plot has no snapshot with the name.
+func (snapshotName) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	v.SetString(arg)
+	return nil
+}
+
+// Options returns the names of the snapshots of the plot that the player is currently in.
+func (snapshotName) Options(source cmd.Source) []string {
+	p := source.(*player.Player)
+	h, ok := plot.LookupHandler(p)
+	if !ok {
+		return nil
+	}
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		return nil
+	}
+	snapshots, _ := h.DB().Snapshots(pos)
+	names := make([]string, 0, len(snapshots))
+	for _, s := range snapshots {
+		names = append(names, s.Name)
+	}
+	return names
+}
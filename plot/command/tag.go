@@ -0,0 +1,114 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"reflect"
+	"strings"
+)
+
+// TagAdd implements the /plot tag add command. It adds a tag, such as 'medieval', to the plot that the player
+// is currently in, so that other players may find the plot by searching for the tag.
+type TagAdd struct {
+	Tag  cmd.SubCommand `cmd:"tag"`
+	Add  cmd.SubCommand `cmd:"add"`
+	Name string         `cmd:"tag"`
+}
+
+// Run ...
+func (t TagAdd) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	tag := strings.ToLower(t.Name)
+	if !plot.ValidTag(tag) {
+		output.Errorf("Tags must be 2-24 characters long and may only contain letters, numbers and '-'.")
+		return
+	}
+	if current.HasTag(tag) {
+		output.Errorf("This plot already has the tag %v.", tag)
+		return
+	}
+	if len(current.Tags) >= plot.MaxTags {
+		output.Errorf("A plot may have at most %v tags.", plot.MaxTags)
+		return
+	}
+	if err := h.DB().AddTag(pos, current, tag); err != nil {
+		output.Errorf("Failed adding tag, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>The tag %v was added to the plot.</green>", f, f, tag))
+}
+
+// TagRemove implements the /plot tag remove command. It removes a tag from the plot that the player is
+// currently in.
+type TagRemove struct {
+	Tag    cmd.SubCommand `cmd:"tag"`
+	Remove cmd.SubCommand `cmd:"remove"`
+	Name   plotTag        `cmd:"tag"`
+}
+
+// Run ...
+func (t TagRemove) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	tag := strings.ToLower(string(t.Name))
+	if !current.HasTag(tag) {
+		output.Errorf("This plot does not have the tag %v.", tag)
+		return
+	}
+	if err := h.DB().RemoveTag(pos, current, tag); err != nil {
+		output.Errorf("Failed removing tag, please try again later. (%v)", err)
+		return
+	}
+	f := current.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>The tag %v was removed from the plot.</green>", f, f, tag))
+}
+
+// plotTag is a tag of the plot that the player is currently in.
+type plotTag string
+
+// Type ...
+func (plotTag) Type() string {
+	return "PlotTag"
+}
+
+// Parse reads any tag, so that a helpful error may be shown if the plot does not have the tag.
+func (plotTag) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	v.SetString(arg)
+	return nil
+}
+
+// Options returns the tags of the plot that the player is currently in.
+func (plotTag) Options(source cmd.Source) []string {
+	p := source.(*player.Player)
+	h, ok := plot.LookupHandler(p)
+	if !ok {
+		return nil
+	}
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		return nil
+	}
+	if pl, err := h.DB().Plot(pos); err == nil {
+		return pl.Tags
+	}
+	return nil
+}
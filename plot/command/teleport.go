@@ -6,33 +6,55 @@ import (
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/plots/plot"
 	"github.com/sandertv/gophertunnel/minecraft/text"
+	"reflect"
 	"strconv"
 )
 
 // Teleport implements a /plot tp command which may be used to teleport to a specific plot owned by the
-// player.
+// player, to any plot with an alias or to any plot by its ID.
 type Teleport struct {
 	Tp cmd.SubCommand `cmd:"tp"`
 	// Number is the number of the plot to teleport to. These numbers may be found by running /p list.
+	// Alternatively, the alias of a plot or the ID of a plot, such as '3;-2', may be passed.
 	Number plotNumber `cmd:"number"`
 }
 
 // Run ...
-func (t Teleport) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+func (t Teleport) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
 	p := source.(*player.Player)
 	h, _ := plot.LookupHandler(p)
 
-	plotPositions := h.PlotPositions()
+	number, err := strconv.Atoi(string(t.Number))
+	if err != nil {
+		pos, err := plot.ParsePosition(string(t.Number))
+		if err != nil {
+			if pos, err = h.DB().PlotByAlias(string(t.Number)); err != nil {
+				output.Errorf("Unknown plot %v. Use a plot number, alias or ID such as 3;-2.", t.Number)
+				return
+			}
+		}
+		pl, err := h.DB().Plot(pos)
+		if err != nil {
+			p.Teleport(pos.TeleportPosition(tx, h.Settings()))
+			output.Printf(text.Colourf("<green>Successfully teleported to the free plot %v.</green>", pos))
+			return
+		}
+		if pl.Denies(p.UUID()) {
+			output.Errorf("You are denied from visiting this plot.")
+			return
+		}
+		p.Teleport(pl.SpawnPosition(tx, pos, h.Settings()))
 
-	number, _ := strconv.Atoi(string(t.Number))
-	if number < 1 || number > len(plotPositions) {
+		f := pl.ColourToFormat()
+		output.Printf(text.Colourf("<%v>■</%v> <green>Successfully teleported to %v by %v.</green>", f, f, pl.Name(), pl.OwnerName))
+		return
+	}
+	pos, pl, err := h.DB().PlotByNumber(p.UUID(), number)
+	if err != nil {
 		output.Errorf("Unknown plot with number %v. Use /p list to get a list of plots to teleport to.", t.Number)
 		return
 	}
-	pl := h.Plots()[number-1]
-	pos := plotPositions[number-1]
-
-	p.Teleport(pos.TeleportPosition(h.Settings()))
+	p.Teleport(pl.SpawnPosition(tx, pos, h.Settings()))
 
 	f := pl.ColourToFormat()
 	output.Printf(text.Colourf("<%v>■</%v> <green>Successfully teleported to your plot.</green>", f, f))
@@ -46,13 +68,30 @@ func (plotNumber) Type() string {
 	return "PlotNumber"
 }
 
-// Options returns a number for every plot the player has.
+// Parse reads any plot number, alias or ID. Unlike with regular enums, the value does not need to be one of
+// the options, so that aliases and IDs of plots of other players may also be passed.
+func (plotNumber) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	v.SetString(arg)
+	return nil
+}
+
+// Options returns a number for every plot the player has, and the aliases of those plots.
 func (plotNumber) Options(source cmd.Source) []string {
 	p := source.(*player.Player)
 	h, _ := plot.LookupHandler(p)
-	m := make([]string, len(h.Plots()))
-	for i := range h.Plots() {
-		m[i] = strconv.Itoa(i + 1)
+	plots := h.Plots()
+	m := make([]string, 0, len(plots))
+	for _, pl := range plots {
+		m = append(m, strconv.Itoa(pl.Number))
+	}
+	for _, pl := range plots {
+		if pl.Alias != "" {
+			m = append(m, pl.Alias)
+		}
 	}
 	return m
 }
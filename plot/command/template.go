@@ -0,0 +1,131 @@
+package command
+
+import (
+	"errors"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"os"
+	"reflect"
+)
+
+// TemplateSave implements the /plot template save command. It saves the blocks of the plot that the admin is
+// currently in as a template, which players may build into plots they claim using /plot claim <template>.
+type TemplateSave struct {
+	adminOnly
+	Template cmd.SubCommand `cmd:"template"`
+	Save     cmd.SubCommand `cmd:"save"`
+	Name     string         `cmd:"name"`
+}
+
+// Run ...
+func (t TemplateSave) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, ok := currentPlot(p, h)
+	if !ok {
+		output.Error("You are not currently in a plot.")
+		return
+	}
+	if !plot.ValidTemplateName(t.Name) {
+		output.Errorf("Invalid template name %v. Names may be up to 24 letters, digits, - and _ long.", t.Name)
+		return
+	}
+	if err := h.DB().SaveTemplate(tx, pos, t.Name); err != nil {
+		output.Errorf("Failed saving template, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>Saved the plot as template %v. Players may use it with /p claim %v.</green>", t.Name, t.Name))
+}
+
+// TemplateDelete implements the /plot template delete command. It deletes a template, after which it can no
+// longer be used to claim plots. Plots already built from the template are unaffected.
+type TemplateDelete struct {
+	adminOnly
+	Template cmd.SubCommand `cmd:"template"`
+	Delete   cmd.SubCommand `cmd:"delete"`
+	Name     templateName   `cmd:"name"`
+}
+
+// Run ...
+func (t TemplateDelete) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	if err := h.DB().RemoveTemplate(string(t.Name)); errors.Is(err, os.ErrNotExist) {
+		output.Errorf("Unknown template %v.", t.Name)
+		return
+	} else if err != nil {
+		output.Errorf("Failed deleting template, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>Template %v was deleted.</green>", t.Name))
+}
+
+// TemplateList implements the /plot template list command. It lists all templates that plots may be claimed
+// with.
+type TemplateList struct {
+	Template cmd.SubCommand `cmd:"template"`
+	List     cmd.SubCommand `cmd:"list"`
+}
+
+// Run ...
+func (TemplateList) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	names, err := h.DB().Templates()
+	if err != nil {
+		output.Errorf("Failed reading templates, please try again later. (%v)", err)
+		return
+	}
+	output.Printf(text.Colourf("<green>Templates:</green> <grey>%v</grey>", listOrNone(names)))
+}
+
+// loadTemplate loads the template with the name passed and checks if it fits in a plot. If not, an error is
+// written to the cmd.Output and false is returned.
+func loadTemplate(h *plot.PlayerHandler, name string, output *cmd.Output) (*plot.Build, bool) {
+	template, err := h.DB().LoadTemplate(name)
+	if errors.Is(err, os.ErrNotExist) {
+		output.Errorf("Unknown template %v. Use /p template list to see all templates.", name)
+		return nil, false
+	} else if err != nil {
+		output.Errorf("Failed reading template, please try again later. (%v)", err)
+		return nil, false
+	}
+	if _, ok := pasteHeight(h, template, output); !ok {
+		return nil, false
+	}
+	return template, true
+}
+
+// templateName is the name of a plot template.
+type templateName string
+
+// Type ...
+func (templateName) Type() string {
+	return "PlotTemplate"
+}
+
+// Parse reads any template name, so that a helpful error may be shown if the template does not exist.
+func (templateName) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	v.SetString(arg)
+	return nil
+}
+
+// Options returns the names of all templates.
+func (templateName) Options(source cmd.Source) []string {
+	h, ok := plot.LookupHandler(source.(*player.Player))
+	if !ok {
+		return nil
+	}
+	names, _ := h.DB().Templates()
+	return names
+}
@@ -0,0 +1,130 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"time"
+)
+
+// Transfer implements the /plot transfer command. It may be used by the owner of a plot to offer the plot to
+// another player, who must accept the offer using /plot transfer accept.
+type Transfer struct {
+	Transfer cmd.SubCommand `cmd:"transfer"`
+	Targets  []cmd.Target   `cmd:"player"`
+}
+
+// Run ...
+func (t Transfer) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, current, ok := ownedPlot(p, h, output)
+	if !ok {
+		return
+	}
+	if len(t.Targets) != 1 {
+		output.Error("Please specify exactly one player.")
+		return
+	}
+	target, ok := t.Targets[0].(*player.Player)
+	if !ok || target.UUID() == p.UUID() {
+		output.Error("Plots can only be transferred to other players.")
+		return
+	}
+	targetHandler, ok := plot.LookupHandler(target)
+	if !ok {
+		output.Errorf("%v cannot receive plots right now.", target.Name())
+		return
+	}
+	targetHandler.OfferTransfer(pos, p.UUID())
+
+	f := current.ColourToFormat()
+	target.Message(text.Colourf("<%v>■</%v> <green>%v offered to transfer a plot to you. Use <white>/p transfer accept</white> or <white>/p transfer deny</white> within a minute.</green>", f, f, p.Name()))
+	output.Printf(text.Colourf("<%v>■</%v> <green>Offered the plot to %v. They must accept the offer within a minute.</green>", f, f, target.Name()))
+}
+
+// TransferAccept implements the /plot transfer accept command. It accepts the last plot offered to the player
+// using /plot transfer.
+type TransferAccept struct {
+	Transfer cmd.SubCommand `cmd:"transfer"`
+	Accept   cmd.SubCommand `cmd:"accept"`
+}
+
+// Run ...
+func (TransferAccept) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	pos, from, ok := h.TransferOffer()
+	if !ok {
+		output.Error("You do not have a pending plot transfer.")
+		return
+	}
+	current, err := h.DB().Plot(pos)
+	if err != nil || current.Owner != from {
+		output.Error("The plot offered to you is no longer owned by the player that offered it.")
+		return
+	}
+	plots := h.Plots()
+	if len(plots) >= h.MaximumPlots() {
+		output.Errorf("You have reached the maximum amount of plot claims. (%v/%v)", len(plots), h.MaximumPlots())
+		return
+	}
+	previousName := current.OwnerName
+	c := generateRandomColour(plots)
+
+	// The plot is changed on a copy, so that the plot in the DB is left unchanged if the transfer fails.
+	current = current.Clone()
+	current.SetRole(p.UUID(), plot.RoleVisitor)
+	current.Owner, current.OwnerName, current.Colour, current.Claimed = p.UUID(), p.Name(), c.String(), time.Now()
+	current.Audit("transfer", from, previousName, p.Name())
+	if err := h.DB().TransferPlot(pos, current, from); err != nil {
+		output.Errorf("Failed transferring plot, please try again later. (%v)", err)
+		return
+	}
+	if err := h.ReloadPlotPositions(); err != nil {
+		output.Errorf("Failed transferring plot, please try again later. (%v)", err)
+		return
+	}
+	pos.SetBorder(tx, h.Settings(), block.Concrete{Colour: c})
+
+	f := current.ColourToFormat()
+	for e := range tx.Players() {
+		if previous := e.(*player.Player); previous.UUID() == from {
+			if previousHandler, ok := plot.LookupHandler(previous); ok {
+				_ = previousHandler.ReloadPlotPositions()
+			}
+			previous.Message(text.Colourf("<%v>■</%v> <green>%v accepted the transfer of your plot.</green>", f, f, p.Name()))
+		}
+	}
+	output.Printf(text.Colourf("<%v>■</%v> <green>Successfully received the plot of %v. (%v/%v)</green>", f, f, previousName, len(plots)+1, h.MaximumPlots()))
+}
+
+// TransferDeny implements the /plot transfer deny command. It denies the last plot offered to the player
+// using /plot transfer.
+type TransferDeny struct {
+	Transfer cmd.SubCommand `cmd:"transfer"`
+	Deny     cmd.SubCommand `cmd:"deny"`
+}
+
+// Run ...
+func (TransferDeny) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	_, from, ok := h.TransferOffer()
+	if !ok {
+		output.Error("You do not have a pending plot transfer.")
+		return
+	}
+	for e := range tx.Players() {
+		if previous := e.(*player.Player); previous.UUID() == from {
+			previous.Message(text.Colourf("<red>%v denied the transfer of your plot.</red>", p.Name()))
+		}
+	}
+	output.Printf(text.Colourf("<green>Denied the plot transfer.</green>"))
+}
@@ -0,0 +1,139 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strings"
+)
+
+// trashPageSize is the amount of plots shown on a single page of /plot trash all.
+const trashPageSize = 10
+
+// Trash implements the /plot trash command. It lists the deleted plots of the player that may still be
+// restored using /plot restore.
+type Trash struct {
+	Trash cmd.SubCommand `cmd:"trash"`
+}
+
+// Run ...
+func (Trash) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	trash, ok := readTrash(p, h, output)
+	if !ok {
+		return
+	}
+	var str strings.Builder
+	for n, a := range trash {
+		str.WriteString(text.Colourf("\n<white>%v:</white> %v", n+1, trashEntry(h.DB(), a)))
+	}
+	output.Printf(text.Colourf("<green>Your deleted plots:</green>") + str.String())
+}
+
+// TrashAll implements the /plot trash all command. It lists the deleted plots of all players. Only admins
+// may browse the trash of all players.
+type TrashAll struct {
+	adminOnly
+	Trash cmd.SubCommand    `cmd:"trash"`
+	All   cmd.SubCommand    `cmd:"all"`
+	Page  cmd.Optional[int] `cmd:"page"`
+}
+
+// Run ...
+func (t TrashAll) Run(source cmd.Source, output *cmd.Output, _ *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	trash, err := h.DB().Archives(uuid.Nil, plot.ArchiveDeleted)
+	if err != nil {
+		output.Errorf("Failed reading trash, please try again later. (%v)", err)
+		return
+	}
+	if len(trash) == 0 {
+		output.Printf(text.Colourf("<green>The trash is empty.</green>"))
+		return
+	}
+	pages := (len(trash) + trashPageSize - 1) / trashPageSize
+	page := t.Page.LoadOr(1)
+	if page < 1 || page > pages {
+		output.Errorf("Unknown page %v. (1-%v)", page, pages)
+		return
+	}
+	offset := (page - 1) * trashPageSize
+
+	var str strings.Builder
+	for _, a := range trash[offset:min(offset+trashPageSize, len(trash))] {
+		str.WriteString(text.Colourf("\n<white>%v:</white> %v", a.Plot.OwnerName, trashEntry(h.DB(), a)))
+	}
+	output.Printf(text.Colourf("<green>Trash (page %v/%v):</green>", page, pages) + str.String())
+}
+
+// Restore implements the /plot restore command. It restores a deleted plot of the player, selected by the
+// number shown in /plot trash, or the plot deleted most recently if no number is passed. The plot is restored
+// at its original location if it is still free, or at a free plot nearby otherwise.
+type Restore struct {
+	Restore cmd.SubCommand    `cmd:"restore"`
+	Number  cmd.Optional[int] `cmd:"number"`
+}
+
+// Run ...
+func (r Restore) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	trash, ok := readTrash(p, h, output)
+	if !ok {
+		return
+	}
+	n := r.Number.LoadOr(len(trash))
+	if n < 1 || n > len(trash) {
+		output.Errorf("Unknown deleted plot with number %v. (1-%v)", n, len(trash))
+		return
+	}
+	if len(h.PlotPositions()) >= h.MaximumPlots() {
+		output.Errorf("You have reached the maximum amount of plot claims. (%v/%v)", len(h.PlotPositions()), h.MaximumPlots())
+		return
+	}
+	a := trash[n-1]
+	pos, err := h.DB().RestoreArchive(tx, a)
+	if err != nil {
+		output.Errorf("Failed restoring plot, please try again later. (%v)", err)
+		return
+	}
+	f := a.Plot.ColourToFormat()
+	if pos != a.Pos {
+		output.Printf(text.Colourf("<%v>■</%v> <green>The plot was restored at %v, because its original location was claimed.</green>", f, f, pos))
+		return
+	}
+	output.Printf(text.Colourf("<%v>■</%v> <green>The plot was restored at its original location %v.</green>", f, f, pos))
+}
+
+// trashEntry formats a deleted plot in the trash as a single line.
+func trashEntry(db *plot.DB, a *plot.Archive) string {
+	f := a.Plot.ColourToFormat()
+	expires := "kept until restored"
+	if t := db.TrashExpires(a); !t.IsZero() {
+		expires = "kept until " + formatTime(t)
+	}
+	return text.Colourf("<%v>■</%v> <white>%v</white> <grey>(%v), deleted at %v, %v</grey>", f, f, a.Plot.Name(), a.Pos, formatTime(a.Archived), expires)
+}
+
+// readTrash reads the deleted plots of the player passed. If the player has no deleted plots or they could
+// not be read, an error is written to the cmd.Output and false is returned.
+func readTrash(p *player.Player, h *plot.PlayerHandler, output *cmd.Output) ([]*plot.Archive, bool) {
+	trash, err := h.DB().Archives(p.UUID(), plot.ArchiveDeleted)
+	if err != nil {
+		output.Errorf("Failed reading trash, please try again later. (%v)", err)
+		return nil, false
+	}
+	if len(trash) == 0 {
+		output.Errorf("You don't have any deleted plots.")
+		return nil, false
+	}
+	return trash, true
+}
@@ -0,0 +1,127 @@
+package command
+
+import (
+	"github.com/df-mc/dragonfly/server/cmd"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/plots/plot"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"reflect"
+	"strconv"
+)
+
+// Visit implements the /plot visit command. It may be used to teleport to a plot of any player, including
+// players that are currently offline. The plot may be selected using its number in the list of plots of the
+// player, or using its alias.
+type Visit struct {
+	Visit  cmd.SubCommand       `cmd:"visit"`
+	Owner  ownerName            `cmd:"player"`
+	Number cmd.Optional[string] `cmd:"number"`
+}
+
+// Run ...
+func (v Visit) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	id, err := h.DB().PlayerByName(string(v.Owner))
+	if err != nil {
+		output.Errorf("Unknown player %v.", v.Owner)
+		return
+	}
+	positions, err := h.DB().PlayerPlots(id)
+	if err != nil || len(positions) == 0 {
+		output.Errorf("%v does not have any plots.", v.Owner)
+		return
+	}
+	var (
+		pos = positions[0]
+		pl  *plot.Plot
+	)
+	arg, ok := v.Number.Load()
+	if !ok {
+		// No plot was specified, so we visit the first plot that the player claimed.
+		pl, err = h.DB().Plot(pos)
+	} else if number, convErr := strconv.Atoi(arg); convErr == nil {
+		pos, pl, err = h.DB().PlotByNumber(id, number)
+	} else if pos, err = h.DB().PlotByAlias(arg); err == nil {
+		if pl, err = h.DB().Plot(pos); err == nil && pl.Owner != id {
+			output.Errorf("%v does not own a plot with alias %v.", v.Owner, arg)
+			return
+		}
+	}
+	if err != nil {
+		output.Errorf("%v does not have a plot %v. Use /p list %v to get a list of their plots.", v.Owner, arg, v.Owner)
+		return
+	}
+	if pl.Denies(p.UUID()) {
+		output.Errorf("You are denied from visiting this plot.")
+		return
+	}
+	p.Teleport(pl.SpawnPosition(tx, pos, h.Settings()))
+
+	f := pl.ColourToFormat()
+	output.Printf(text.Colourf("<%v>■</%v> <green>Successfully teleported to %v by %v.</green>", f, f, pl.Name(), pl.OwnerName))
+}
+
+// ownerName is the name of a player owning at least one plot.
+type ownerName string
+
+// Type ...
+func (ownerName) Type() string {
+	return "PlotOwner"
+}
+
+// Parse reads the name of any player, as the name of an owner that has not recently been seen may not be
+// present in the options.
+func (ownerName) Parse(line *cmd.Line, v reflect.Value) error {
+	arg, ok := line.Next()
+	if !ok {
+		return cmd.ErrInsufficientArgs
+	}
+	v.SetString(arg)
+	return nil
+}
+
+// Options returns the names of all players owning at least one plot.
+func (ownerName) Options(source cmd.Source) []string {
+	h, ok := plot.LookupHandler(source.(*player.Player))
+	if !ok {
+		return nil
+	}
+	return h.DB().OwnerNames()
+}
+
+// VisitResult implements the /plot visit <number> command. It teleports the player to one of the plots found
+// in its last search using /plot search.
+type VisitResult struct {
+	Visit  cmd.SubCommand `cmd:"visit"`
+	Number int            `cmd:"number"`
+}
+
+// Run ...
+func (v VisitResult) Run(source cmd.Source, output *cmd.Output, tx *world.Tx) {
+	p := source.(*player.Player)
+	h, _ := plot.LookupHandler(p)
+
+	results := h.SearchResults()
+	if len(results) == 0 {
+		output.Errorf("You have not searched for plots yet. Use /p search to search for plots.")
+		return
+	}
+	if v.Number < 1 || v.Number > len(results) {
+		output.Errorf("Unknown search result %v. (1-%v)", v.Number, len(results))
+		return
+	}
+	pos := results[v.Number-1]
+	pl, err := h.DB().Plot(pos)
+	if err != nil {
+		output.Errorf("This plot no longer exists.")
+		return
+	}
+	if pl.Denies(p.UUID()) {
+		output.Errorf("You are denied from visiting this plot.")
+		return
+	}
+	teleportToPlot(p, h, pos, output, tx)
+}
@@ -0,0 +1,165 @@
+package plot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// commentPrefix is the prefix of keys that hold comments left on plots. The key is followed by the hash of the
+// Position of the plot, the time at which the comment was left as a big endian uint64 and the UUID of the
+// author, so that the comments on a plot are ordered from oldest to newest.
+const commentPrefix = "plots:comment:"
+
+const (
+	// MaxCommentLength is the maximum amount of characters that a single Comment may have.
+	MaxCommentLength = 256
+	// maxComments is the maximum amount of comments stored for a single plot. If a comment is added to a plot
+	// that already has this many comments, the oldest comment is removed.
+	maxComments = 50
+)
+
+// Comment is a comment left on a plot by a player, typically a visitor of the plot. The owner of the plot may
+// read the comments on its plots in its inbox.
+type Comment struct {
+	// Plot is the Position of the plot that the comment was left on.
+	Plot Position `json:"-"`
+	// Author is the UUID of the player that left the comment and AuthorName is its name at the time.
+	Author     uuid.UUID
+	AuthorName string
+	// Text is the text of the comment.
+	Text string
+	// Time is the time at which the comment was left.
+	Time time.Time
+	// Read specifies if the owner of the plot has read the comment.
+	Read bool `json:",omitempty"`
+
+	key []byte
+}
+
+// commentKey returns the key under which the Comment passed is stored.
+func commentKey(pos Position, c Comment) []byte {
+	key := append(append([]byte(commentPrefix), pos.Hash()...), make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(key)-8:], uint64(c.Time.UnixNano()))
+	return append(key, c.Author[:]...)
+}
+
+// commentRange returns the range of keys of the comments on the plot at the Position passed.
+func commentRange(pos Position) *util.Range {
+	return util.BytesPrefix(append([]byte(commentPrefix), pos.Hash()...))
+}
+
+// AddComment adds a Comment to the plot at the Position passed. If the plot already has the maximum amount of
+// comments, the oldest comments are removed.
+func (db *DB) AddComment(pos Position, c Comment) error {
+	if len(c.Text) > MaxCommentLength {
+		return fmt.Errorf("add comment: comment may be at most %v characters long", MaxCommentLength)
+	}
+	comments, err := db.Comments(pos)
+	if err != nil {
+		return fmt.Errorf("add comment: %w", err)
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("add comment: %w", err)
+	}
+	batch := new(leveldb.Batch)
+	batch.Put(commentKey(pos, c), b)
+	for i := 0; i <= len(comments)-maxComments; i++ {
+		batch.Delete(comments[i].key)
+	}
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return fmt.Errorf("add comment: %w", err)
+	}
+	return nil
+}
+
+// Comments returns all comments left on the plot at the Position passed, ordered from oldest to newest.
+func (db *DB) Comments(pos Position) ([]Comment, error) {
+	it := db.ldb.NewIterator(commentRange(pos), nil)
+	defer it.Release()
+
+	var comments []Comment
+	for it.Next() {
+		c := Comment{Plot: pos, key: append([]byte(nil), it.Key()...)}
+		if err := json.Unmarshal(it.Value(), &c); err != nil {
+			return nil, fmt.Errorf("comments: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("comments: %w", err)
+	}
+	return comments, nil
+}
+
+// Inbox returns the comments left on all plots owned by the player with the UUID passed, ordered from newest
+// to oldest.
+func (db *DB) Inbox(owner uuid.UUID) ([]Comment, error) {
+	positions, err := db.PlayerPlots(owner)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("inbox: %w", err)
+	}
+	var inbox []Comment
+	for _, pos := range positions {
+		comments, err := db.Comments(pos)
+		if err != nil {
+			return nil, fmt.Errorf("inbox: %w", err)
+		}
+		inbox = append(inbox, comments...)
+	}
+	slices.SortFunc(inbox, func(a, b Comment) int {
+		return b.Time.Compare(a.Time)
+	})
+	return inbox, nil
+}
+
+// MarkRead marks all Comments passed as read.
+func (db *DB) MarkRead(comments []Comment) error {
+	batch := new(leveldb.Batch)
+	for _, c := range comments {
+		if c.Read {
+			continue
+		}
+		c.Read = true
+		b, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("mark read: %w", err)
+		}
+		batch.Put(c.key, b)
+	}
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return fmt.Errorf("mark read: %w", err)
+	}
+	return nil
+}
+
+// DeleteComments deletes all Comments passed.
+func (db *DB) DeleteComments(comments []Comment) error {
+	batch := new(leveldb.Batch)
+	for _, c := range comments {
+		batch.Delete(c.key)
+	}
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return fmt.Errorf("delete comments: %w", err)
+	}
+	return nil
+}
+
+// removeComments removes all comments on the plot at the Position passed by adding the deletions to the batch
+// passed.
+func (db *DB) removeComments(batch *leveldb.Batch, pos Position) {
+	it := db.ldb.NewIterator(commentRange(pos), nil)
+	defer it.Release()
+	for it.Next() {
+		batch.Delete(append([]byte(nil), it.Key()...))
+	}
+}
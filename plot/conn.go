@@ -0,0 +1,144 @@
+package plot
+
+import (
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"sync"
+)
+
+// WrapListener wraps the server.Listener returned by the function passed so that the time and weather shown
+// to players may be changed by the flags of the plot they are in. It should be applied to all functions in
+// server.Config.Listeners before the server is created.
+func WrapListener(f func(conf server.Config) (server.Listener, error)) func(conf server.Config) (server.Listener, error) {
+	return func(conf server.Config) (server.Listener, error) {
+		l, err := f(conf)
+		if err != nil {
+			return nil, err
+		}
+		return listener{Listener: l}, nil
+	}
+}
+
+// listener wraps around a server.Listener to wrap all connections accepted in a conn.
+type listener struct {
+	server.Listener
+}
+
+// Accept accepts the next connection of the server.Listener and wraps it in a conn.
+func (l listener) Accept() (session.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	id, _ := uuid.Parse(c.IdentityData().Identity)
+	wrapped := &conn{Conn: c, id: id}
+	conns.Store(id, wrapped)
+	return wrapped, nil
+}
+
+// Disconnect disconnects the connection passed, unwrapping it if it is a conn.
+func (l listener) Disconnect(c session.Conn, reason string) error {
+	if wrapped, ok := c.(*conn); ok {
+		c = wrapped.Conn
+	}
+	return l.Listener.Disconnect(c, reason)
+}
+
+// conns holds all conns currently open, indexed by the UUID of the player.
+var conns sync.Map
+
+// conn wraps around a session.Conn. It overwrites the time and weather sent to the player if the plot that
+// the player is in has a FlagTime or FlagWeather set.
+type conn struct {
+	session.Conn
+	id uuid.UUID
+
+	mu      sync.Mutex
+	time    *int32
+	weather *Weather
+}
+
+// WritePacket writes a packet to the connection, overwriting its contents if it changes the time or weather
+// that was overwritten for the player.
+func (c *conn) WritePacket(pk packet.Packet) error {
+	c.mu.Lock()
+	switch pk := pk.(type) {
+	case *packet.SetTime:
+		if c.time != nil {
+			pk.Time = *c.time
+		}
+	case *packet.LevelEvent:
+		if c.weather != nil {
+			switch pk.EventType {
+			case packet.LevelEventStartRaining, packet.LevelEventStopRaining:
+				*pk = *weatherEvent(*c.weather != WeatherClear, packet.LevelEventStartRaining, packet.LevelEventStopRaining)
+			case packet.LevelEventStartThunderstorm, packet.LevelEventStopThunderstorm:
+				*pk = *weatherEvent(*c.weather == WeatherThunder, packet.LevelEventStartThunderstorm, packet.LevelEventStopThunderstorm)
+			}
+		}
+	}
+	c.mu.Unlock()
+	return c.Conn.WritePacket(pk)
+}
+
+// Close closes the connection and removes it from the conns map.
+func (c *conn) Close() error {
+	conns.CompareAndDelete(c.id, c)
+	return c.Conn.Close()
+}
+
+// setTime overwrites the time shown to the player with the time passed. If nil is passed, the override is
+// removed, if present, and the time passed as fallback is shown.
+func (c *conn) setTime(t *int, fallback int) {
+	c.mu.Lock()
+	if t == nil && c.time == nil {
+		c.mu.Unlock()
+		return
+	}
+	if t == nil {
+		c.time = nil
+	} else {
+		v := int32(*t)
+		c.time = &v
+		fallback = *t
+	}
+	c.mu.Unlock()
+	_ = c.Conn.WritePacket(&packet.SetTime{Time: int32(fallback)})
+}
+
+// setWeather overwrites the weather shown to the player with the Weather passed. If nil is passed, the
+// override is removed, if present, and the fallback Weather is shown.
+func (c *conn) setWeather(w *Weather, fallback Weather) {
+	c.mu.Lock()
+	if w == nil && c.weather == nil {
+		c.mu.Unlock()
+		return
+	}
+	c.weather = w
+	if w != nil {
+		fallback = *w
+	}
+	c.mu.Unlock()
+	_ = c.Conn.WritePacket(weatherEvent(fallback != WeatherClear, packet.LevelEventStartRaining, packet.LevelEventStopRaining))
+	_ = c.Conn.WritePacket(weatherEvent(fallback == WeatherThunder, packet.LevelEventStartThunderstorm, packet.LevelEventStopThunderstorm))
+}
+
+// weatherEvent returns a packet.LevelEvent that starts or stops rain or a thunderstorm.
+func weatherEvent(start bool, startEvent, stopEvent int32) *packet.LevelEvent {
+	if start {
+		return &packet.LevelEvent{EventType: startEvent, EventData: 30000}
+	}
+	return &packet.LevelEvent{EventType: stopEvent}
+}
+
+// lookupConn looks up the conn of the player with the UUID passed. False is returned if the player did not
+// connect through a listener wrapped using WrapListener.
+func lookupConn(id uuid.UUID) (*conn, bool) {
+	v, ok := conns.Load(id)
+	if !ok {
+		return nil, false
+	}
+	return v.(*conn), true
+}
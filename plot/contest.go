@@ -0,0 +1,292 @@
+package plot
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"github.com/google/uuid"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// contestPrefix is the prefix of keys that hold a Contest. The key is followed by the lower-case name of the
+// contest.
+const contestPrefix = "plots:contest:"
+
+// ContestPhase is the phase that a Contest is in at a specific time.
+type ContestPhase uint8
+
+const (
+	// ContestUpcoming is the phase of a Contest before its submission window opens.
+	ContestUpcoming ContestPhase = iota
+	// ContestSubmissions is the phase of a Contest in which players may submit their plots. Plots submitted
+	// cannot be edited until the phase ends.
+	ContestSubmissions
+	// ContestVoting is the phase of a Contest after the submission window, in which the plots submitted may
+	// be voted on.
+	ContestVoting
+	// ContestFinished is the phase of a Contest after voting ended. The results may be published by an admin.
+	ContestFinished
+)
+
+// String ...
+func (p ContestPhase) String() string {
+	switch p {
+	case ContestUpcoming:
+		return "upcoming"
+	case ContestSubmissions:
+		return "open for submissions"
+	case ContestVoting:
+		return "voting"
+	case ContestFinished:
+		return "finished"
+	}
+	panic("should never happen")
+}
+
+// Contest is a build contest with a theme. Players may submit one of their plots during the submission window
+// of the contest, after which the plots may be voted on until voting ends.
+type Contest struct {
+	// Name is the unique name of the contest, used to refer to it in commands.
+	Name string
+	// Theme is the theme that plots submitted to the contest should be built in.
+	Theme string
+	// Start and End are the times at which the submission window opens and closes. VotingEnd is the time at
+	// which voting, which starts at End, ends.
+	Start, End, VotingEnd time.Time
+	// Judges holds the UUIDs of the players that may vote on plots submitted. If empty, all players may vote.
+	Judges []uuid.UUID `json:",omitempty"`
+	// Entries holds all plots submitted to the contest.
+	Entries []ContestEntry
+	// Votes maps the UUID of every player that voted to the Position of the plot it voted for.
+	Votes map[uuid.UUID]Position
+	// Published is true if the results of the contest were published.
+	Published bool `json:",omitempty"`
+}
+
+// ContestEntry is a plot submitted to a Contest.
+type ContestEntry struct {
+	Plot      Position
+	Owner     uuid.UUID
+	OwnerName string
+	Submitted time.Time
+	// Votes is the amount of votes for the entry. It is only set in entries returned by Contest.Results.
+	Votes int `json:"-"`
+}
+
+var (
+	// ErrContestPhase is returned by Contest.Submit and Contest.Vote if the contest is not in the right phase.
+	ErrContestPhase = errors.New("contest is not in the right phase")
+	// ErrAlreadySubmitted is returned by Contest.Submit if the player already submitted a plot.
+	ErrAlreadySubmitted = errors.New("a plot was already submitted by the player")
+	// ErrNotJudge is returned by Contest.Vote if the contest has judges and the player is not one of them.
+	ErrNotJudge = errors.New("player is not a judge")
+	// ErrNotSubmitted is returned by Contest.Vote if the plot voted for was not submitted.
+	ErrNotSubmitted = errors.New("plot was not submitted")
+	// ErrOwnEntry is returned by Contest.Vote if a player votes for its own plot.
+	ErrOwnEntry = errors.New("cannot vote for own plot")
+)
+
+// Phase returns the ContestPhase of the Contest at the time passed.
+func (c *Contest) Phase(t time.Time) ContestPhase {
+	switch {
+	case t.Before(c.Start):
+		return ContestUpcoming
+	case t.Before(c.End):
+		return ContestSubmissions
+	case t.Before(c.VotingEnd):
+		return ContestVoting
+	}
+	return ContestFinished
+}
+
+// Entry returns the ContestEntry of the plot at the Position passed. False is returned if the plot was not
+// submitted to the Contest.
+func (c *Contest) Entry(pos Position) (ContestEntry, bool) {
+	i := slices.IndexFunc(c.Entries, func(e ContestEntry) bool { return e.Plot == pos })
+	if i == -1 {
+		return ContestEntry{}, false
+	}
+	return c.Entries[i], true
+}
+
+// Submit submits the Plot at the Position passed to the Contest. Each player may only submit a single plot.
+func (c *Contest) Submit(pos Position, p *Plot) error {
+	if c.Phase(time.Now()) != ContestSubmissions {
+		return ErrContestPhase
+	}
+	if slices.ContainsFunc(c.Entries, func(e ContestEntry) bool { return e.Owner == p.Owner }) {
+		return ErrAlreadySubmitted
+	}
+	c.Entries = append(c.Entries, ContestEntry{Plot: pos, Owner: p.Owner, OwnerName: p.OwnerName, Submitted: time.Now()})
+	return nil
+}
+
+// Vote votes for the plot at the Position passed on behalf of the player with the UUID passed. If the player
+// voted before, its previous vote is replaced.
+func (c *Contest) Vote(id uuid.UUID, pos Position) error {
+	if c.Phase(time.Now()) != ContestVoting {
+		return ErrContestPhase
+	}
+	if len(c.Judges) != 0 && !slices.Contains(c.Judges, id) {
+		return ErrNotJudge
+	}
+	e, ok := c.Entry(pos)
+	if !ok {
+		return ErrNotSubmitted
+	}
+	if e.Owner == id {
+		return ErrOwnEntry
+	}
+	if c.Votes == nil {
+		c.Votes = map[uuid.UUID]Position{}
+	}
+	c.Votes[id] = pos
+	return nil
+}
+
+// Results returns the entries of the Contest with their amount of votes, sorted from most to least votes.
+// Entries with the same amount of votes are sorted by the time they were submitted.
+func (c *Contest) Results() []ContestEntry {
+	results := slices.Clone(c.Entries)
+	for _, pos := range c.Votes {
+		if i := slices.IndexFunc(results, func(e ContestEntry) bool { return e.Plot == pos }); i != -1 {
+			results[i].Votes++
+		}
+	}
+	slices.SortStableFunc(results, func(a, b ContestEntry) int {
+		return cmp.Compare(b.Votes, a.Votes)
+	})
+	return results
+}
+
+// contestKey returns the key under which the Contest with the name passed is stored.
+func contestKey(name string) []byte {
+	return []byte(contestPrefix + strings.ToLower(name))
+}
+
+// loadContests reads all contests stored in the DB, so that they may be returned by Contests.
+func (db *DB) loadContests() error {
+	it := db.ldb.NewIterator(util.BytesPrefix([]byte(contestPrefix)), nil)
+	defer it.Release()
+	for it.Next() {
+		var c Contest
+		if err := json.Unmarshal(it.Value(), &c); err != nil {
+			return err
+		}
+		db.contests[strings.ToLower(c.Name)] = &c
+	}
+	return it.Error()
+}
+
+// Contests returns all contests stored in the DB, sorted by the start of their submission window.
+func (db *DB) Contests() []*Contest {
+	contests := make([]*Contest, 0, len(db.contests))
+	for _, c := range db.contests {
+		contests = append(contests, c)
+	}
+	slices.SortFunc(contests, func(a, b *Contest) int {
+		return a.Start.Compare(b.Start)
+	})
+	return contests
+}
+
+// Contest looks up a Contest by its name. Names are case-insensitive. False is returned if no contest with the
+// name exists.
+func (db *DB) Contest(name string) (*Contest, bool) {
+	c, ok := db.contests[strings.ToLower(name)]
+	return c, ok
+}
+
+// StoreContest stores the Contest passed in the DB. If a contest with the same name already exists, it is
+// replaced.
+func (db *DB) StoreContest(c *Contest) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("store contest: %w", err)
+	}
+	if err := db.ldb.Put(contestKey(c.Name), b, nil); err != nil {
+		return fmt.Errorf("store contest: %w", err)
+	}
+	db.contests[strings.ToLower(c.Name)] = c
+	return nil
+}
+
+// RemoveContest removes the Contest with the name passed from the DB.
+func (db *DB) RemoveContest(name string) error {
+	if err := db.ldb.Delete(contestKey(name), nil); err != nil {
+		return fmt.Errorf("remove contest: %w", err)
+	}
+	delete(db.contests, strings.ToLower(name))
+	return nil
+}
+
+// ContestLocked checks if the plot at the Position passed was submitted to a Contest that is currently open
+// for submissions. Such plots may not be edited until the submission window closes.
+func (db *DB) ContestLocked(pos Position) bool {
+	now := time.Now()
+	for _, c := range db.contests {
+		if c.Phase(now) != ContestSubmissions {
+			continue
+		}
+		if _, ok := c.Entry(pos); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// changeEntries adds changes to the entries of plots in contests that are not yet finished to the batch passed.
+// Entries of plots at Positions mapped to another Position are moved to that Position along with their votes,
+// for example because the plot was moved. Entries of plots at Positions mapped to nil are withdrawn, for
+// example because the plot was removed or transferred. The contests changed are returned and must be passed
+// to cacheContests once the batch was written.
+func (db *DB) changeEntries(batch *leveldb.Batch, changes map[Position]*Position) ([]*Contest, error) {
+	var changed []*Contest
+	now := time.Now()
+	for _, c := range db.contests {
+		if c.Phase(now) == ContestFinished || !slices.ContainsFunc(c.Entries, func(e ContestEntry) bool {
+			_, ok := changes[e.Plot]
+			return ok
+		}) {
+			continue
+		}
+		cp := *c
+		cp.Entries = nil
+		for _, e := range c.Entries {
+			if to, ok := changes[e.Plot]; ok && to == nil {
+				continue
+			} else if ok {
+				e.Plot = *to
+			}
+			cp.Entries = append(cp.Entries, e)
+		}
+		cp.Votes = maps.Clone(c.Votes)
+		for id, pos := range cp.Votes {
+			if to, ok := changes[pos]; ok && to == nil {
+				delete(cp.Votes, id)
+			} else if ok {
+				cp.Votes[id] = *to
+			}
+		}
+		b, err := json.Marshal(&cp)
+		if err != nil {
+			return nil, err
+		}
+		batch.Put(contestKey(cp.Name), b)
+		changed = append(changed, &cp)
+	}
+	return changed, nil
+}
+
+// cacheContests replaces the cached contests with the same names as the contests passed.
+func (db *DB) cacheContests(contests []*Contest) {
+	for _, c := range contests {
+		db.contests[strings.ToLower(c.Name)] = c
+	}
+}
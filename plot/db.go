@@ -2,10 +2,14 @@ package plot
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/df-mc/goleveldb/leveldb"
 	"github.com/google/uuid"
+	"iter"
 	"os"
+	"slices"
+	"strings"
 )
 
 // DB handles access to the plots leveldb database. It provides abstraction over the database layer so that
@@ -14,6 +18,29 @@ type DB struct {
 	ldb      *leveldb.DB
 	settings Settings
 	cache    map[Position]*Plot
+	// dir is the directory of the DB. Files that are too large to be stored in the DB, such as the blocks of
+	// archived plots, are stored in subdirectories of it.
+	dir string
+	// owners maps the UUIDs of all players owning at least one plot to their names.
+	owners map[uuid.UUID]string
+	// contests holds all contests stored in the DB, indexed by their lower-case names.
+	contests map[string]*Contest
+}
+
+// Keys of plots are 8 bytes long and keys of the plots of players are 16 bytes long. All other keys stored in
+// the DB are prefixed with one of the prefixes below, which makes sure they are never shorter than 9 bytes.
+const (
+	// aliasPrefix is the prefix of keys that map the alias of a plot to its Position.
+	aliasPrefix = "plots:alias:"
+	// namePrefix is the prefix of keys that map the UUID of a player to the name it last joined with.
+	namePrefix = "plots:name:"
+	// ownerPrefix is the prefix of keys that map the lower-case name of a player to its UUID.
+	ownerPrefix = "plots:owner:"
+)
+
+// aliasKey returns the key used to store the Position of the plot with the alias passed.
+func aliasKey(alias string) []byte {
+	return []byte(aliasPrefix + strings.ToLower(alias))
 }
 
 // OpenDB opens the directory passed as a leveldb database for plots. If the directory does not yet exist, it
@@ -28,7 +55,107 @@ func OpenDB(dir string, settings Settings) (*DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error opening leveldb database: %w", err)
 	}
-	return &DB{ldb: ldb, settings: settings, cache: map[Position]*Plot{}}, nil
+	db := &DB{ldb: ldb, dir: dir, settings: settings, cache: map[Position]*Plot{}, owners: map[uuid.UUID]string{}, contests: map[string]*Contest{}}
+	if err := db.loadOwners(); err != nil {
+		_ = ldb.Close()
+		return nil, fmt.Errorf("error loading plot owners: %w", err)
+	}
+	if err := db.loadContests(); err != nil {
+		_ = ldb.Close()
+		return nil, fmt.Errorf("error loading contests: %w", err)
+	}
+	if err := db.numberPlots(); err != nil {
+		_ = ldb.Close()
+		return nil, fmt.Errorf("error numbering plots: %w", err)
+	}
+	return db, nil
+}
+
+// numberPlots gives a Plot.Number to all plots that were claimed before plots were numbered. The plots of each
+// owner are numbered in the order they were claimed.
+func (db *DB) numberPlots() error {
+	var err error
+	owners := map[uuid.UUID]struct{}{}
+	for _, p := range db.Plots(&err) {
+		if p.Number == 0 && p.Owned() {
+			owners[p.Owner] = struct{}{}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	for owner := range owners {
+		positions, err := db.PlayerPlots(owner)
+		if errors.Is(err, leveldb.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		for _, pos := range positions {
+			p, err := db.Plot(pos)
+			if err != nil || p.Number != 0 {
+				continue
+			}
+			p = p.Clone()
+			p.Number = db.FreeNumber(positions)
+			if err := db.StorePlot(pos, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadOwners reads the owners of all plots stored in the DB, so that they may be returned by OwnerNames.
+func (db *DB) loadOwners() error {
+	var err error
+	for _, p := range db.Plots(&err) {
+		db.owners[p.Owner] = p.OwnerName
+	}
+	return err
+}
+
+// Plots returns an iterator over all plots stored in the DB, in no particular order. Plots are read from the
+// cache where possible, but are not added to it. If reading the plots fails, iteration stops and the error is
+// written to the error pointer passed.
+func (db *DB) Plots(err *error) iter.Seq2[Position, *Plot] {
+	return func(yield func(Position, *Plot) bool) {
+		it := db.ldb.NewIterator(nil, nil)
+		defer it.Release()
+		for it.Next() {
+			if len(it.Key()) != 8 {
+				// Not a plot, but one of the other keys stored in the DB.
+				continue
+			}
+			pos := posFromHash(it.Key())
+			p, ok := db.cache[pos]
+			if !ok {
+				p = new(Plot)
+				if e := json.Unmarshal(it.Value(), p); e != nil {
+					*err = fmt.Errorf("plots: %w", e)
+					return
+				}
+			}
+			if !yield(pos, p) {
+				return
+			}
+		}
+		if e := it.Error(); e != nil {
+			*err = fmt.Errorf("plots: %w", e)
+		}
+	}
+}
+
+// OwnerNames returns the names of all players that own at least one plot, sorted alphabetically.
+func (db *DB) OwnerNames() []string {
+	names := make([]string, 0, len(db.owners))
+	for _, name := range db.owners {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return names
 }
 
 // Plot attempts to read a Plot from the DB at the Position passed.
@@ -58,15 +185,120 @@ func (db *DB) StorePlot(pos Position, p *Plot) error {
 		return fmt.Errorf("store plot: %w", err)
 	}
 	db.cache[pos] = p
+	db.owners[p.Owner] = p.OwnerName
 	return nil
 }
 
-// RemovePlot attempts to remove a Plot at a specific Position in the DB.
+// RemovePlot attempts to remove a Plot at a specific Position in the DB. Any indexes of the plot, such as its
+// alias, its tags, its position on leaderboards and its entry in the review queue, are removed with it, as
+// are the votes, comments, snapshots and logged block changes of the plot. The plot is withdrawn from any
+// contest that is not yet finished.
 func (db *DB) RemovePlot(pos Position) error {
-	if err := db.ldb.Delete(pos.Hash(), nil); err != nil {
+	batch := new(leveldb.Batch)
+	batch.Delete(pos.Hash())
+	if p, err := db.Plot(pos); err == nil {
+		db.unindex(batch, pos, p)
+	}
+	db.removeVotes(batch, pos)
+	db.removeComments(batch, pos)
+	db.removeSnapshots(batch, pos)
+	db.removeBlockLog(batch, pos)
+	db.lowerCursor(batch, pos)
+	contests, err := db.changeEntries(batch, map[Position]*Position{pos: nil})
+	if err != nil {
+		return fmt.Errorf("remove plot: %w", err)
+	}
+	if err := db.ldb.Write(batch, nil); err != nil {
 		return fmt.Errorf("remove plot: %w", err)
 	}
 	delete(db.cache, pos)
+	db.cacheContests(contests)
+	if err := os.RemoveAll(db.snapshotDir(pos)); err != nil {
+		return fmt.Errorf("remove plot: %w", err)
+	}
+	return nil
+}
+
+// index adds the keys of all indexes of the Plot at the Position passed to the batch passed. These are the
+// indexes of its alias, its tags, its positions on leaderboards and its entry in the review queue. If the
+// alias of the plot is already taken by another plot, the alias of the plot is removed.
+func (db *DB) index(batch *leveldb.Batch, pos Position, p *Plot) {
+	if p.Alias != "" {
+		if other, err := db.PlotByAlias(p.Alias); err == nil && other != pos {
+			p.Alias = ""
+		} else {
+			batch.Put(aliasKey(p.Alias), pos.Hash())
+		}
+	}
+	for _, tag := range p.Tags {
+		batch.Put(tagKey(tag, pos), nil)
+	}
+	for _, l := range Leaderboards() {
+		if score := l.score(p); score != 0 {
+			batch.Put(l.key(pos, score), nil)
+		}
+	}
+	if p.Review == ReviewPending {
+		batch.Put(reviewKey(pos, p), nil)
+	}
+}
+
+// unindex adds the deletion of the keys of all indexes of the Plot at the Position passed to the batch passed.
+// It is the opposite of index.
+func (db *DB) unindex(batch *leveldb.Batch, pos Position, p *Plot) {
+	if p.Alias != "" {
+		batch.Delete(aliasKey(p.Alias))
+	}
+	for _, tag := range p.Tags {
+		batch.Delete(tagKey(tag, pos))
+	}
+	for _, l := range Leaderboards() {
+		if score := l.score(p); score != 0 {
+			batch.Delete(l.key(pos, score))
+		}
+	}
+	if p.Review == ReviewPending {
+		batch.Delete(reviewKey(pos, p))
+	}
+}
+
+// ErrAliasTaken is returned by DB.SetAlias if the alias passed is already used by another plot.
+var ErrAliasTaken = errors.New("alias is already taken")
+
+// PlotByAlias looks up the Position of the plot with the alias passed. Aliases are case-insensitive.
+func (db *DB) PlotByAlias(alias string) (Position, error) {
+	val, err := db.ldb.Get(aliasKey(alias), nil)
+	if err != nil {
+		return Position{}, fmt.Errorf("plot by alias: %w", err)
+	}
+	return posFromHash(val), nil
+}
+
+// SetAlias changes the alias of the Plot at the Position passed to the alias passed and stores the plot. An
+// empty alias removes the alias of the plot. If another plot already has the alias, ErrAliasTaken is returned.
+func (db *DB) SetAlias(pos Position, p *Plot, alias string) error {
+	if alias != "" {
+		if other, err := db.PlotByAlias(alias); err == nil && other != pos {
+			return fmt.Errorf("set alias: %w", ErrAliasTaken)
+		}
+	}
+	batch := new(leveldb.Batch)
+	if p.Alias != "" {
+		batch.Delete(aliasKey(p.Alias))
+	}
+	if alias != "" {
+		batch.Put(aliasKey(alias), pos.Hash())
+	}
+	p.Alias = alias
+	b, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("set alias: %w", err)
+	}
+	batch.Put(pos.Hash(), b)
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return fmt.Errorf("set alias: %w", err)
+	}
+	db.cache[pos] = p
 	return nil
 }
 
@@ -92,6 +324,132 @@ func (db *DB) StorePlayerPlots(id uuid.UUID, positions []Position) error {
 	if err := db.ldb.Put(id[:], val, nil); err != nil {
 		return fmt.Errorf("store player plots: %w", err)
 	}
+	if len(positions) == 0 {
+		delete(db.owners, id)
+	}
+	return nil
+}
+
+// PlotByNumber looks up the plot with the Plot.Number passed among the plots of the player with the UUID
+// passed. leveldb.ErrNotFound is returned if the player has no plot with the number.
+func (db *DB) PlotByNumber(owner uuid.UUID, number int) (Position, *Plot, error) {
+	positions, err := db.PlayerPlots(owner)
+	if err != nil {
+		return Position{}, nil, fmt.Errorf("plot by number: %w", err)
+	}
+	for _, pos := range positions {
+		if p, err := db.Plot(pos); err == nil && p.Number == number {
+			return pos, p, nil
+		}
+	}
+	return Position{}, nil, fmt.Errorf("plot by number: %w", leveldb.ErrNotFound)
+}
+
+// FreeNumber returns the lowest Plot.Number, starting at 1, that is not used by any of the plots at the
+// Positions passed. It is used to number a new plot of a player.
+func (db *DB) FreeNumber(positions []Position) int {
+	used := make(map[int]struct{}, len(positions))
+	for _, pos := range positions {
+		if p, err := db.Plot(pos); err == nil {
+			used[p.Number] = struct{}{}
+		}
+	}
+	n := 1
+	for ; ; n++ {
+		if _, ok := used[n]; !ok {
+			return n
+		}
+	}
+}
+
+// TransferPlot stores the Plot passed, which must already have its new owner set, and moves its Position from
+// the plot positions of the previous owner to those of the new owner. The plot is given the first
+// Plot.Number free among the plots of the new owner. All changes are written at once, so that either all or
+// none of them are applied. The contest entries and snapshots of the plot are removed, as they belong to the
+// previous owner. The Plot passed should be a copy made using Plot.Clone, so that the cached plot only changes
+// if the changes were written.
+func (db *DB) TransferPlot(pos Position, p *Plot, previous uuid.UUID) error {
+	fromPositions, err := db.PlayerPlots(previous)
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return fmt.Errorf("transfer plot: %w", err)
+	}
+	toPositions, err := db.PlayerPlots(p.Owner)
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return fmt.Errorf("transfer plot: %w", err)
+	}
+	fromPositions = slices.DeleteFunc(fromPositions, func(other Position) bool { return other == pos })
+	p.Number = db.FreeNumber(toPositions)
+	toPositions = append(toPositions, pos)
+
+	batch := new(leveldb.Batch)
+	for key, v := range map[string]any{string(pos.Hash()): p, string(previous[:]): fromPositions, string(p.Owner[:]): toPositions} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("transfer plot: %w", err)
+		}
+		batch.Put([]byte(key), b)
+	}
+	// The entries of the plot in contests were submitted by the previous owner, so they are withdrawn.
+	contests, err := db.changeEntries(batch, map[Position]*Position{pos: nil})
+	if err != nil {
+		return fmt.Errorf("transfer plot: %w", err)
+	}
+	// The snapshots of the plot were saved by the previous owner too, and would otherwise count towards the
+	// snapshots of the new owner.
+	db.removeSnapshots(batch, pos)
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return fmt.Errorf("transfer plot: %w", err)
+	}
+	db.cache[pos] = p
+	db.cacheContests(contests)
+	db.owners[p.Owner] = p.OwnerName
+	if len(fromPositions) == 0 {
+		delete(db.owners, previous)
+	}
+	if err := os.RemoveAll(db.snapshotDir(pos)); err != nil {
+		return fmt.Errorf("transfer plot: %w", err)
+	}
+	return nil
+}
+
+// PlayerName looks up the name that the player with the UUID passed last joined with.
+func (db *DB) PlayerName(id uuid.UUID) (string, error) {
+	val, err := db.ldb.Get(append([]byte(namePrefix), id[:]...), nil)
+	if err != nil {
+		return "", fmt.Errorf("player name: %w", err)
+	}
+	return string(val), nil
+}
+
+// PlayerByName looks up the UUID of the player that last joined with the name passed. Names are
+// case-insensitive.
+func (db *DB) PlayerByName(name string) (uuid.UUID, error) {
+	val, err := db.ldb.Get([]byte(ownerPrefix+strings.ToLower(name)), nil)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("player by name: %w", err)
+	}
+	id, err := uuid.FromBytes(val)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("player by name: %w", err)
+	}
+	return id, nil
+}
+
+// StorePlayerName stores the name that the player with the UUID passed joined with, so that it may be looked
+// up using PlayerName and PlayerByName.
+func (db *DB) StorePlayerName(id uuid.UUID, name string) error {
+	batch := new(leveldb.Batch)
+	if previous, err := db.PlayerName(id); err == nil && !strings.EqualFold(previous, name) {
+		batch.Delete([]byte(ownerPrefix + strings.ToLower(previous)))
+	}
+	batch.Put(append([]byte(namePrefix), id[:]...), []byte(name))
+	batch.Put([]byte(ownerPrefix+strings.ToLower(name)), id[:])
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return fmt.Errorf("store player name: %w", err)
+	}
+	if _, ok := db.owners[id]; ok {
+		db.owners[id] = name
+	}
 	return nil
 }
 
@@ -0,0 +1,98 @@
+package plot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+// ExpiringPlot is a plot of an owner that has been inactive for longer than Settings.ExpiryPeriod. The plot
+// is archived once the grace period of Settings.ExpiryGrace has passed, unless the owner returns before then.
+type ExpiringPlot struct {
+	Pos  Position
+	Plot *Plot
+	// LastActive is the last time at which the owner of the plot was active.
+	LastActive time.Time
+	// Expires is the time at which the plot will be archived.
+	Expires time.Time
+}
+
+// ExpiringPlots returns all plots of owners that have been inactive for longer than Settings.ExpiryPeriod,
+// ordered by the time at which they will be archived. If Settings.ExpiryPeriod is 0, no plots expire.
+func (db *DB) ExpiringPlots() ([]ExpiringPlot, error) {
+	if db.settings.ExpiryPeriod <= 0 {
+		return nil, nil
+	}
+	var (
+		err        error
+		now        = time.Now()
+		lastActive = map[uuid.UUID]time.Time{}
+		expiring   []ExpiringPlot
+	)
+	for pos, p := range db.Plots(&err) {
+		if Online(p.Owner) {
+			continue
+		}
+		last, ok := lastActive[p.Owner]
+		if !ok {
+			a, found := db.Activity(p.Owner)
+			if !found {
+				// The activity of the owner was never recorded, for example because it last joined before
+				// activity was tracked. We start tracking it now, so that its plots don't expire immediately.
+				a.LastLogin = now
+				_ = db.StoreActivity(p.Owner, a)
+			}
+			last = a.LastActive()
+			lastActive[p.Owner] = last
+		}
+		if now.Sub(last) < db.settings.ExpiryPeriod {
+			continue
+		}
+		expiring = append(expiring, ExpiringPlot{Pos: pos, Plot: p, LastActive: last, Expires: last.Add(db.settings.ExpiryPeriod + db.settings.ExpiryGrace)})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expiring plots: %w", err)
+	}
+	slices.SortFunc(expiring, func(a, b ExpiringPlot) int {
+		return a.Expires.Compare(b.Expires)
+	})
+	return expiring, nil
+}
+
+// ExpiredPlots returns the plots returned by ExpiringPlots of which the grace period has passed. These plots
+// should be archived using ArchiveExpired.
+func (db *DB) ExpiredPlots() ([]ExpiringPlot, error) {
+	expiring, err := db.ExpiringPlots()
+	if err != nil {
+		return nil, fmt.Errorf("expired plots: %w", err)
+	}
+	for i, e := range expiring {
+		if time.Now().Before(e.Expires) {
+			return expiring[:i], nil
+		}
+	}
+	return expiring, nil
+}
+
+// ArchiveExpired archives the plot of the ExpiringPlot passed, as returned by ExpiredPlots. Every plot is
+// archived in a transaction of its own, so the plot is checked again first: False is returned if it was removed
+// or transferred, or if its owner was active since it was returned by ExpiredPlots.
+func (db *DB) ArchiveExpired(tx *world.Tx, e ExpiringPlot) (bool, error) {
+	p, err := db.Plot(e.Pos)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("archive expired: %w", err)
+	}
+	if a, _ := db.Activity(p.Owner); p.Owner != e.Plot.Owner || Online(p.Owner) || a.LastActive().After(e.LastActive) {
+		return false, nil
+	}
+	if _, err := db.ArchivePlot(tx, e.Pos, p, ArchiveExpired); err != nil {
+		return false, fmt.Errorf("archive expired: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,195 @@
+package plot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/df-mc/goleveldb/leveldb"
+	"strings"
+	"sync"
+)
+
+// Filter is a condition that plots must meet to be included in the results of, for example, /plot random.
+// Filters are parsed from strings such as 'owner:Steve' using ParseFilter.
+type Filter interface {
+	// Match checks if the Plot at the Position passed meets the condition of the Filter.
+	Match(pos Position, p *Plot) bool
+}
+
+// IndexedFilter is a Filter that can look up the plots that may match it using an index in the DB, so that
+// not every plot has to be read to find the plots matching it.
+type IndexedFilter interface {
+	Filter
+	// Candidates returns the Positions of all plots that may match the Filter. Plots not returned never
+	// match it.
+	Candidates(db *DB) ([]Position, error)
+}
+
+// FilterFunc is a function that implements Filter.
+type FilterFunc func(pos Position, p *Plot) bool
+
+// Match ...
+func (f FilterFunc) Match(pos Position, p *Plot) bool {
+	return f(pos, p)
+}
+
+// FilterParser creates a Filter from the operator and value passed. The operator is one of ':', '=', '>',
+// '<', '>=' and '<='. An error should be returned if the operator is not supported by the filter or if the
+// value could not be parsed.
+type FilterParser func(op, value string) (Filter, error)
+
+var (
+	filterMu sync.RWMutex
+	filters  = map[string]FilterParser{}
+)
+
+// RegisterFilter registers a FilterParser under the key passed, so that ParseFilter may parse filters such
+// as 'key:value'. Registering a key twice replaces the FilterParser previously registered.
+func RegisterFilter(key string, parser FilterParser) {
+	filterMu.Lock()
+	defer filterMu.Unlock()
+	filters[strings.ToLower(key)] = parser
+}
+
+// FilterKeys returns the keys of all filters registered.
+func FilterKeys() []string {
+	filterMu.RLock()
+	defer filterMu.RUnlock()
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ParseFilter parses a Filter from a string in the format 'key<op>value', such as 'owner:Steve' or
+// 'likes>5'. The key must have been registered using RegisterFilter.
+func ParseFilter(s string) (Filter, error) {
+	i := strings.IndexAny(s, ":=<>")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid filter '%v': expected format key:value", s)
+	}
+	key, op, value := strings.ToLower(s[:i]), s[i:i+1], s[i+1:]
+	if (op == "<" || op == ">") && strings.HasPrefix(value, "=") {
+		op, value = op+"=", value[1:]
+	}
+	filterMu.RLock()
+	parser, ok := filters[key]
+	filterMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown filter '%v'", key)
+	}
+	f, err := parser(op, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter '%v': %w", s, err)
+	}
+	return f, nil
+}
+
+// ParseFilters parses a list of filters separated by spaces, such as 'owner:Steve likes>5', using
+// ParseFilter.
+func ParseFilters(s string) ([]Filter, error) {
+	fields := strings.Fields(s)
+	l := make([]Filter, 0, len(fields))
+	for _, field := range fields {
+		f, err := ParseFilter(field)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, f)
+	}
+	return l, nil
+}
+
+// MatchAll checks if the Plot at the Position passed matches all Filters passed.
+func MatchAll(filters []Filter, pos Position, p *Plot) bool {
+	for _, f := range filters {
+		if !f.Match(pos, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// compare compares a and b using the operator passed, which is one of ':', '=', '>', '<', '>=' and '<='.
+func compare(op string, a, b int) bool {
+	switch op {
+	case ">":
+		return a > b
+	case "<":
+		return a < b
+	case ">=":
+		return a >= b
+	case "<=":
+		return a <= b
+	}
+	return a == b
+}
+
+// ownerFilter is a Filter that matches plots owned by the player with a specific name. It is an IndexedFilter,
+// so that searching for the plots of a player does not require reading every plot.
+type ownerFilter string
+
+// Match ...
+func (f ownerFilter) Match(_ Position, p *Plot) bool {
+	return strings.EqualFold(p.OwnerName, string(f))
+}
+
+// Candidates ...
+func (f ownerFilter) Candidates(db *DB) ([]Position, error) {
+	id, err := db.PlayerByName(string(f))
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	positions, err := db.PlayerPlots(id)
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return nil, err
+	}
+	return positions, nil
+}
+
+func init() {
+	RegisterFilter("owner", func(op, value string) (Filter, error) {
+		if op != ":" && op != "=" {
+			return nil, fmt.Errorf("owner only supports ':'")
+		}
+		return ownerFilter(value), nil
+	})
+}
+
+// FindPlots returns the Positions of all plots in the DB that match all Filters passed, in no particular
+// order. If any of the filters is an IndexedFilter, only the candidates of that filter are read. Otherwise,
+// all plots in the DB are read.
+func (db *DB) FindPlots(filters []Filter) ([]Position, error) {
+	for _, f := range filters {
+		indexed, ok := f.(IndexedFilter)
+		if !ok {
+			continue
+		}
+		candidates, err := indexed.Candidates(db)
+		if err != nil {
+			return nil, fmt.Errorf("find plots: %w", err)
+		}
+		positions := make([]Position, 0, len(candidates))
+		for _, pos := range candidates {
+			if p, err := db.Plot(pos); err == nil && MatchAll(filters, pos, p) {
+				positions = append(positions, pos)
+			}
+		}
+		return positions, nil
+	}
+	var (
+		err       error
+		positions []Position
+	)
+	for pos, p := range db.Plots(&err) {
+		if MatchAll(filters, pos, p) {
+			positions = append(positions, pos)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find plots: %w", err)
+	}
+	return positions, nil
+}
@@ -0,0 +1,222 @@
+package plot
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Flag is a setting that the owner of a Plot may set to change the behaviour of the plot. Flags are
+// registered using RegisterFlag, after which they may be set by players using the /plot flag command.
+// Flags are typically created using NewFlag.
+type Flag interface {
+	// Name returns the name of the Flag. The name is used to refer to the flag in commands and to store the
+	// value of the flag in a Plot.
+	Name() string
+	// Description returns a short description of what the Flag does.
+	Description() string
+	// SetString parses the string passed and sets the resulting value of the Flag on the Plot passed. An
+	// error is returned if the string could not be parsed.
+	SetString(p *Plot, s string) error
+	// String returns the value of the Flag on the Plot passed as a string. False is returned if the flag was
+	// not set on the plot.
+	String(p *Plot) (string, bool)
+}
+
+// TypedFlag is a Flag that holds a value of type T. Its methods may be used to read and write the value of
+// the flag on a Plot directly.
+type TypedFlag[T any] struct {
+	name, description string
+	parse             func(s string) (T, error)
+}
+
+// NewFlag creates a new TypedFlag with a name and a description. The parse function is used to parse a value
+// of the flag from a string passed by a player.
+func NewFlag[T any](name, description string, parse func(s string) (T, error)) TypedFlag[T] {
+	return TypedFlag[T]{name: name, description: description, parse: parse}
+}
+
+// Name ...
+func (f TypedFlag[T]) Name() string {
+	return f.name
+}
+
+// Description ...
+func (f TypedFlag[T]) Description() string {
+	return f.description
+}
+
+// Value returns the value of the flag on the Plot passed. False is returned if the flag was not set.
+func (f TypedFlag[T]) Value(p *Plot) (T, bool) {
+	var v T
+	data, ok := p.Flags[f.name]
+	if !ok {
+		return v, false
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, false
+	}
+	return v, true
+}
+
+// Set sets the value of the flag on the Plot passed.
+func (f TypedFlag[T]) Set(p *Plot, v T) {
+	data, _ := json.Marshal(v)
+	if p.Flags == nil {
+		p.Flags = map[string]json.RawMessage{}
+	}
+	p.Flags[f.name] = data
+}
+
+// SetString ...
+func (f TypedFlag[T]) SetString(p *Plot, s string) error {
+	v, err := f.parse(s)
+	if err != nil {
+		return err
+	}
+	f.Set(p, v)
+	return nil
+}
+
+// String ...
+func (f TypedFlag[T]) String(p *Plot) (string, bool) {
+	v, ok := f.Value(p)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+// UnsetFlag removes a Flag with the name passed from the Plot, so that its default behaviour applies again.
+func (p *Plot) UnsetFlag(name string) {
+	delete(p.Flags, name)
+}
+
+var (
+	flagMu sync.RWMutex
+	flags  = map[string]Flag{}
+)
+
+// RegisterFlag registers a Flag so that it may be set by players. Registering a Flag with the same name as
+// a Flag registered before replaces it.
+func RegisterFlag(f Flag) {
+	flagMu.Lock()
+	defer flagMu.Unlock()
+	flags[strings.ToLower(f.Name())] = f
+}
+
+// FlagByName looks up a registered Flag by its name. False is returned if no Flag with the name was
+// registered.
+func FlagByName(name string) (Flag, bool) {
+	flagMu.RLock()
+	defer flagMu.RUnlock()
+	f, ok := flags[strings.ToLower(name)]
+	return f, ok
+}
+
+// RegisteredFlags returns a list of all Flags registered, sorted by their names.
+func RegisteredFlags() []Flag {
+	flagMu.RLock()
+	defer flagMu.RUnlock()
+	l := make([]Flag, 0, len(flags))
+	for _, f := range flags {
+		l = append(l, f)
+	}
+	slices.SortFunc(l, func(a, b Flag) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return l
+}
+
+// Weather is a type of weather that may be shown to players in a Plot using FlagWeather.
+type Weather string
+
+const (
+	WeatherClear   Weather = "clear"
+	WeatherRain    Weather = "rain"
+	WeatherThunder Weather = "thunder"
+)
+
+var (
+	// FlagPvP specifies if players may attack other players in the plot. Players cannot attack each other in
+	// plots that do not have this flag set.
+	FlagPvP = NewFlag("pvp", "Allows players to attack each other.", strconv.ParseBool)
+	// FlagTime sets the time of day shown to players in the plot. The time of the world is unaffected.
+	FlagTime = NewFlag("time", "Sets the time of day shown in the plot.", parseTime)
+	// FlagWeather sets the weather shown to players in the plot. The weather of the world is unaffected.
+	FlagWeather = NewFlag("weather", "Sets the weather shown in the plot.", parseWeather)
+	// FlagGameMode sets the game mode of visitors while they are in the plot.
+	FlagGameMode = NewFlag("gamemode", "Sets the game mode of visitors in the plot.", parseGameMode)
+	// FlagFly specifies if players may fly in the plot, regardless of their game mode.
+	FlagFly = NewFlag("fly", "Allows or disallows flying in the plot.", strconv.ParseBool)
+	// FlagGreeting is a message sent to players entering the plot.
+	FlagGreeting = NewFlag("greeting", "Sets a message shown when entering the plot.", parseMessage)
+	// FlagFarewell is a message sent to players leaving the plot.
+	FlagFarewell = NewFlag("farewell", "Sets a message shown when leaving the plot.", parseMessage)
+	// FlagItemDrop specifies if items may be dropped in the plot. If set to false, nobody, including the
+	// owner, can drop items in the plot.
+	FlagItemDrop = NewFlag("item-drop", "Allows or disallows dropping items in the plot.", strconv.ParseBool)
+)
+
+func init() {
+	for _, f := range []Flag{FlagPvP, FlagTime, FlagWeather, FlagGameMode, FlagFly, FlagGreeting, FlagFarewell, FlagItemDrop} {
+		RegisterFlag(f)
+	}
+}
+
+// parseTime parses a time of day from either a number of ticks or a name such as 'night'.
+func parseTime(s string) (int, error) {
+	switch strings.ToLower(s) {
+	case "day":
+		return 1000, nil
+	case "noon":
+		return 6000, nil
+	case "night":
+		return 13000, nil
+	case "midnight":
+		return 18000, nil
+	}
+	t, err := strconv.Atoi(s)
+	if err != nil || t < 0 || t >= 24000 {
+		return 0, fmt.Errorf("time must be day, noon, night, midnight or a number from 0 to 23999")
+	}
+	return t, nil
+}
+
+// parseWeather parses a Weather from its name.
+func parseWeather(s string) (Weather, error) {
+	switch w := Weather(strings.ToLower(s)); w {
+	case WeatherClear, WeatherRain, WeatherThunder:
+		return w, nil
+	}
+	return "", fmt.Errorf("weather must be clear, rain or thunder")
+}
+
+// gameModes maps the names of game modes that may be set using FlagGameMode to their world.GameMode.
+var gameModes = map[string]world.GameMode{
+	"survival":  world.GameModeSurvival,
+	"creative":  world.GameModeCreative,
+	"adventure": world.GameModeAdventure,
+	"spectator": world.GameModeSpectator,
+}
+
+// parseGameMode parses the name of a game mode.
+func parseGameMode(s string) (string, error) {
+	s = strings.ToLower(s)
+	if _, ok := gameModes[s]; !ok {
+		return "", fmt.Errorf("game mode must be survival, creative, adventure or spectator")
+	}
+	return s, nil
+}
+
+// parseMessage parses a message set for a flag such as FlagGreeting.
+func parseMessage(s string) (string, error) {
+	if len(s) > 128 {
+		return "", fmt.Errorf("message may be at most 128 characters long")
+	}
+	return s, nil
+}
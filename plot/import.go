@@ -0,0 +1,202 @@
+package plot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReadSchematic reads a Build from a Sponge .schem file, as created by WorldEdit, read from the io.Reader
+// passed. Both version 2 and version 3 of the format are supported. Because schematics hold blocks of Java
+// Edition, blocks with unknown names or states are mapped to known blocks using decodeBlock, and block entity
+// data is not read. Schematics wider or longer than the maximum width passed are rejected before their blocks
+// are read.
+func ReadSchematic(r io.Reader, maxWidth int) (*Build, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read schematic: %w", err)
+	}
+	var root map[string]any
+	if err := nbt.NewDecoderWithEncoding(gr, nbt.BigEndian).Decode(&root); err != nil {
+		return nil, fmt.Errorf("read schematic: %w", err)
+	}
+	if s, ok := root["Schematic"].(map[string]any); ok {
+		root = s
+	}
+	palette, _ := root["Palette"].(map[string]any)
+	data := byteArray(root["BlockData"])
+	if blocks, ok := root["Blocks"].(map[string]any); ok {
+		// Version 3 of the format holds the palette and the blocks in a separate compound.
+		palette, _ = blocks["Palette"].(map[string]any)
+		data = byteArray(blocks["Data"])
+	}
+	// The dimensions of a schematic are unsigned shorts.
+	width, _ := root["Width"].(int16)
+	height, _ := root["Height"].(int16)
+	length, _ := root["Length"].(int16)
+	w, h, l := int(uint16(width)), int(uint16(height)), int(uint16(length))
+	if h > buildHeight {
+		return nil, fmt.Errorf("read schematic: invalid height %v", h)
+	}
+	if w > maxWidth || l > maxWidth {
+		return nil, fmt.Errorf("read schematic: %vx%v is larger than %vx%v", w, l, maxWidth, maxWidth)
+	}
+	// Every block takes up at least one byte in the block data, so a schematic with less data than blocks is
+	// invalid. Checking this before allocating the blocks prevents invalid dimensions from using lots of memory.
+	if w*h*l > len(data) {
+		return nil, fmt.Errorf("read schematic: %vx%vx%v blocks but only %v bytes of block data", w, h, l, len(data))
+	}
+
+	decoded := map[int32]world.Block{}
+	for state, v := range palette {
+		if n, ok := v.(int32); ok {
+			decoded[n] = decodeJavaState(state)
+		}
+	}
+	b := newBuild(w, h, l)
+	buf := bytes.NewReader(data)
+	for y := 0; y < b.height; y++ {
+		for z := 0; z < b.length; z++ {
+			for x := 0; x < b.width; x++ {
+				n, err := binary.ReadUvarint(buf)
+				if err != nil {
+					return nil, fmt.Errorf("read schematic: block data: %w", err)
+				}
+				b.set(b.index(x, y, z), decoded[int32(n)])
+			}
+		}
+	}
+	return b, nil
+}
+
+// decodeJavaState decodes a block state of Java Edition, such as 'minecraft:oak_stairs[facing=north]', to a
+// world.Block using decodeBlock. Structure voids are decoded as nil.
+func decodeJavaState(state string) world.Block {
+	name, props, _ := strings.Cut(strings.TrimSuffix(state, "]"), "[")
+	properties := map[string]any{}
+	for _, prop := range strings.Split(props, ",") {
+		k, v, ok := strings.Cut(prop, "=")
+		if !ok {
+			continue
+		}
+		switch {
+		case v == "true" || v == "false":
+			properties[k] = boolByte(v == "true")
+		default:
+			if n, err := strconv.Atoi(v); err == nil {
+				properties[k] = int32(n)
+			} else {
+				properties[k] = v
+			}
+		}
+	}
+	return decodeBlock(map[string]any{"name": name, "states": properties})
+}
+
+// boolByte converts a bool to a byte as used in block properties.
+func boolByte(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// byteArray converts an NBT byte array, which is decoded as a fixed size array, to a byte slice.
+func byteArray(v any) []byte {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Array || val.Type().Elem().Kind() != reflect.Uint8 {
+		return nil
+	}
+	b := make([]byte, val.Len())
+	reflect.Copy(reflect.ValueOf(b), val)
+	return b
+}
+
+// Size returns the width, height and length of the Build.
+func (b *Build) Size() (width, height, length int) {
+	return b.width, b.height, b.length
+}
+
+// Y returns the Y at which the lowest layer of the Build was captured. It is 0 for builds that were not
+// captured from a world, such as imported structures.
+func (b *Build) Y() int {
+	return b.y
+}
+
+// Paste places the Build in the plot at the Position passed with its lowest layer at the Y passed. Unlike
+// Build.Place, blocks outside the Build and blocks at structure voids in the Build are left unchanged. If the
+// Build is larger than the plot, it is cut off at the bounds of the plot.
+func (b *Build) Paste(tx *world.Tx, pos Position, settings Settings, y int) {
+	base, _ := pos.Bounds(settings)
+	b.PasteAt(tx, base.Add(cube.Pos{0, y, 0}), settings)
+}
+
+// PasteAt pastes the Build like Build.Paste, but with its lowest corner at the cube.Pos passed, which must be
+// within the bounds of a plot. The Build is cut off at the bounds of that plot.
+func (b *Build) PasteAt(tx *world.Tx, pos cube.Pos, settings Settings) {
+	_, high := PosFromBlockPos(pos, settings).Bounds(settings)
+	tx.BuildStructure(pos, pasted{Build: b, size: [3]int{
+		min(b.width, high[0]-pos[0]+1),
+		min(b.height, buildHeight-pos[1]),
+		min(b.length, high[2]-pos[2]+1),
+	}})
+}
+
+// pasted is a Build as pasted using Build.PasteAt, cut off at a specific size.
+type pasted struct {
+	*Build
+	size [3]int
+}
+
+// Dimensions ...
+func (p pasted) Dimensions() [3]int {
+	return p.size
+}
+
+// importExtensions are the extensions of files that may be imported using DB.LoadImport.
+var importExtensions = []string{".mcstructure", ".schem"}
+
+// Imports returns the names of all files in the imports directory of the DB that may be imported using
+// LoadImport.
+func (db *DB) Imports() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(db.dir, "imports"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("imports: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && slices.Contains(importExtensions, strings.ToLower(filepath.Ext(e.Name()))) {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// LoadImport reads a Build from the file with the name passed in the imports directory of the DB. Files may be
+// .mcstructure or Sponge .schem files.
+func (db *DB) LoadImport(name string) (*Build, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if filepath.Base(name) != name || !slices.Contains(importExtensions, ext) {
+		return nil, fmt.Errorf("load import: invalid file name %v", name)
+	}
+	f, err := os.Open(filepath.Join(db.dir, "imports", name))
+	if err != nil {
+		return nil, fmt.Errorf("load import: %w", err)
+	}
+	defer f.Close()
+	if ext == ".schem" {
+		return ReadSchematic(f, db.settings.PlotWidth)
+	}
+	return ReadStructure(f)
+}
@@ -0,0 +1,76 @@
+package plot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"testing"
+)
+
+// schematic returns a Sponge schematic of version 2 with the dimensions and block data passed. The block data
+// must be a byte array, such as [4]byte, so that it is encoded as an NBT byte array.
+func schematic(t *testing.T, width, height, length int16, data any) *bytes.Buffer {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	err := nbt.NewEncoderWithEncoding(gw, nbt.BigEndian).Encode(map[string]any{
+		"Version": int32(2),
+		"Width":   width,
+		"Height":  height,
+		"Length":  length,
+		"Palette": map[string]any{
+			"minecraft:stone": int32(0),
+			"minecraft:oak_stairs[facing=east,half=bottom,shape=straight,waterlogged=false]": int32(1),
+		},
+		"BlockData": data,
+	})
+	if err != nil {
+		t.Fatalf("encode schematic: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("encode schematic: %v", err)
+	}
+	return &buf
+}
+
+func TestReadSchematic(t *testing.T) {
+	// Blocks in the block data are ordered by Y, then Z and then X.
+	b, err := ReadSchematic(schematic(t, 2, 1, 2, [4]byte{0, 1, 1, 0}), 32)
+	if err != nil {
+		t.Fatalf("read schematic: %v", err)
+	}
+	if w, h, l := b.Size(); w != 2 || h != 1 || l != 2 {
+		t.Fatalf("schematic size = %vx%vx%v, want 2x1x2", w, h, l)
+	}
+	stairs := block.Stairs{Block: block.Planks{Wood: block.OakWood()}, Facing: cube.East}
+	for _, tc := range []struct {
+		x, z int
+		want world.Block
+	}{{0, 0, block.Stone{}}, {1, 0, stairs}, {0, 1, stairs}, {1, 1, block.Stone{}}} {
+		if got, _ := b.At(tc.x, 0, tc.z, nil); world.BlockRuntimeID(got) != world.BlockRuntimeID(tc.want) {
+			t.Errorf("block at %v 0 %v = %v, want %v", tc.x, tc.z, encodeBlock(got), encodeBlock(tc.want))
+		}
+	}
+}
+
+func TestReadSchematicInvalidDimensions(t *testing.T) {
+	for _, tc := range []struct {
+		name                  string
+		width, height, length int16
+	}{
+		{"too wide", 33, 1, 1},
+		{"too long", 1, 1, 33},
+		{"too high", 1, buildHeight + 1, 1},
+		// Dimensions are unsigned, so -1 is read as 65535.
+		{"unsigned width", -1, 1, 1},
+		{"more blocks than data", 32, 200, 32},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ReadSchematic(schematic(t, tc.width, tc.height, tc.length, [1]byte{0}), 32); err == nil {
+				t.Error("ReadSchematic returned no error")
+			}
+		})
+	}
+}
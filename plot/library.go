@@ -0,0 +1,120 @@
+package plot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// libraryExtension is the extension of files in the library of a player. Structures in a library are stored
+// in the same format as snapshots, as written by SaveBuild.
+const libraryExtension = ".build"
+
+// ValidLibraryName checks if the name passed may be used as the name of a structure in a library.
+func ValidLibraryName(name string) bool {
+	return nameRegex.MatchString(name)
+}
+
+// libraryDir returns the directory that holds the structures in the library of the player with the UUID
+// passed.
+func (db *DB) libraryDir(owner uuid.UUID) string {
+	return filepath.Join(db.dir, "library", owner.String())
+}
+
+// Library returns the names of all structures in the personal library of the player with the UUID passed.
+func (db *DB) Library(owner uuid.UUID) ([]string, error) {
+	entries, err := os.ReadDir(db.libraryDir(owner))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("library: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), libraryExtension); ok && !e.IsDir() && ValidLibraryName(name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// LibrarySize returns the total size in bytes of the files of all structures in the library of the player with
+// the UUID passed.
+func (db *DB) LibrarySize(owner uuid.UUID) (int64, error) {
+	names, err := db.Library(owner)
+	if err != nil {
+		return 0, fmt.Errorf("library size: %w", err)
+	}
+	var size int64
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(db.libraryDir(owner), name+libraryExtension))
+		if err != nil {
+			return 0, fmt.Errorf("library size: %w", err)
+		}
+		size += info.Size()
+	}
+	return size, nil
+}
+
+// ErrLibraryFull is returned by DB.SaveToLibrary if saving the structure would make the files in the library
+// of the player larger than Settings.MaximumLibrarySize.
+var ErrLibraryFull = errors.New("library is full")
+
+// SaveToLibrary saves the Build passed in the library of the player with the UUID passed under the name
+// passed. An existing structure with the same name is overwritten. ErrLibraryFull is returned if the library
+// would become larger than Settings.MaximumLibrarySize. The amount of structures in the library is not checked
+// against Settings.MaximumLibrary.
+func (db *DB) SaveToLibrary(owner uuid.UUID, name string, b *Build) error {
+	path := filepath.Join(db.libraryDir(owner), name+libraryExtension)
+	var buf bytes.Buffer
+	if _, err := b.WriteTo(&buf); err != nil {
+		return fmt.Errorf("save to library: %w", err)
+	}
+	if db.settings.MaximumLibrarySize > 0 {
+		size, err := db.LibrarySize(owner)
+		if err != nil {
+			return fmt.Errorf("save to library: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil {
+			// The structure is overwritten, so its current size no longer counts.
+			size -= info.Size()
+		}
+		if size+int64(buf.Len()) > db.settings.MaximumLibrarySize {
+			return fmt.Errorf("save to library: %w", ErrLibraryFull)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("save to library: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0666); err != nil {
+		return fmt.Errorf("save to library: %w", err)
+	}
+	return nil
+}
+
+// LoadFromLibrary reads the structure with the name passed from the library of the player with the UUID
+// passed. os.ErrNotExist is returned if the library holds no structure with the name.
+func (db *DB) LoadFromLibrary(owner uuid.UUID, name string) (*Build, error) {
+	if !ValidLibraryName(name) {
+		return nil, fmt.Errorf("load from library: %w", os.ErrNotExist)
+	}
+	b, err := LoadBuild(filepath.Join(db.libraryDir(owner), name+libraryExtension))
+	if err != nil {
+		return nil, fmt.Errorf("load from library: %w", err)
+	}
+	return b, nil
+}
+
+// RemoveFromLibrary removes the structure with the name passed from the library of the player with the UUID
+// passed. os.ErrNotExist is returned if the library holds no structure with the name.
+func (db *DB) RemoveFromLibrary(owner uuid.UUID, name string) error {
+	if !ValidLibraryName(name) {
+		return fmt.Errorf("remove from library: %w", os.ErrNotExist)
+	}
+	if err := os.Remove(filepath.Join(db.libraryDir(owner), name+libraryExtension)); err != nil {
+		return fmt.Errorf("remove from library: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package plot
+
+import (
+	"os"
+	"testing"
+	_ "unsafe"
+)
+
+// TestMain finalises the block registry before running the tests. This is normally done when the server is
+// created, and is required for looking up blocks by name or runtime ID.
+func TestMain(m *testing.M) {
+	finaliseBlockRegistry()
+	os.Exit(m.Run())
+}
+
+//go:linkname finaliseBlockRegistry github.com/df-mc/dragonfly/server/world.finaliseBlockRegistry
+func finaliseBlockRegistry()
@@ -0,0 +1,245 @@
+package plot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/google/uuid"
+	"os"
+	"slices"
+)
+
+// plotRecord is a key-value pair stored for a plot under one of the prefixes of movedPrefixes, such as a vote
+// or a comment. The key holds only the part after the hash of the Position of the plot. Its fields are exported
+// so that the records of a plot may be kept in an Archive.
+type plotRecord struct {
+	Prefix        string
+	Suffix, Value []byte
+}
+
+// movedPrefixes are the prefixes of keys holding data of a plot that moves with the plot when it is moved
+// using DB.MovePlot or DB.SwapPlots, and that is kept with the plot when it is archived. The logged block
+// changes of a plot are not moved, as the blocks they refer to are no longer in the plot.
+var movedPrefixes = []string{votePrefix, commentPrefix, snapshotPrefix}
+
+// plotRecords reads all records stored for the plot at the Position passed under the prefixes of
+// movedPrefixes.
+func (db *DB) plotRecords(pos Position) ([]plotRecord, error) {
+	var records []plotRecord
+	for _, prefix := range movedPrefixes {
+		start := append([]byte(prefix), pos.Hash()...)
+		it := db.ldb.NewIterator(util.BytesPrefix(start), nil)
+		for it.Next() {
+			records = append(records, plotRecord{
+				Prefix: prefix,
+				Suffix: append([]byte(nil), it.Key()[len(start):]...),
+				Value:  append([]byte(nil), it.Value()...),
+			})
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// putPlotRecords adds the records passed to the batch for the plot at the Position passed.
+func (db *DB) putPlotRecords(batch *leveldb.Batch, pos Position, records []plotRecord) error {
+	for _, r := range records {
+		val := r.Value
+		if r.Prefix == snapshotPrefix {
+			// Snapshots hold the position of their plot, which must be updated.
+			var s Snapshot
+			if err := json.Unmarshal(val, &s); err != nil {
+				return err
+			}
+			s.Pos = pos
+			val, _ = json.Marshal(s)
+		}
+		batch.Put(append(append([]byte(r.Prefix), pos.Hash()...), r.Suffix...), val)
+	}
+	return nil
+}
+
+// removePlotRecords adds the deletion of all records of the plot at the Position passed that are tied to its
+// position to the batch passed.
+func (db *DB) removePlotRecords(batch *leveldb.Batch, pos Position) {
+	db.removeVotes(batch, pos)
+	db.removeComments(batch, pos)
+	db.removeSnapshots(batch, pos)
+	db.removeBlockLog(batch, pos)
+}
+
+// relocate adds the storage of the Plot passed at the Position to, after being moved from the Position from,
+// to the batch passed. The plot must have been unindexed at its old position already. Its home is moved along
+// with the plot.
+func (db *DB) relocate(batch *leveldb.Batch, from, to Position, p *Plot, records []plotRecord) error {
+	if p.Home != nil {
+		offset := to.Absolute(db.settings).Sub(from.Absolute(db.settings))
+		home := p.Home.Add(mgl64.Vec3{float64(offset[0]), 0, float64(offset[2])})
+		p.Home = &home
+	}
+	// The alias of the plot still points to its old position in the DB, so it is indexed manually to prevent
+	// index from removing it.
+	alias := p.Alias
+	p.Alias = ""
+	db.index(batch, to, p)
+	if p.Alias = alias; alias != "" {
+		batch.Put(aliasKey(alias), to.Hash())
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	batch.Put(to.Hash(), b)
+	return db.putPlotRecords(batch, to, records)
+}
+
+// replacePlayerPlot adds the replacement of a Position in the plots of the player with the UUID passed to
+// the batch passed.
+func (db *DB) replacePlayerPlot(batch *leveldb.Batch, id uuid.UUID, old, new Position) error {
+	positions, err := db.PlayerPlots(id)
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return err
+	}
+	if i := slices.Index(positions, old); i != -1 {
+		positions[i] = new
+	}
+	b, err := json.Marshal(positions)
+	if err != nil {
+		return err
+	}
+	batch.Put(id[:], b)
+	return nil
+}
+
+// reloadOwner reloads the plots held by the PlayerHandler of the player with the UUID passed, if it is online.
+func reloadOwner(id uuid.UUID) {
+	if h, ok := handlers.Load(id); ok {
+		_ = h.(*PlayerHandler).ReloadPlotPositions()
+	}
+}
+
+// MovePlot moves the plot at the Position from to the free Position to. The blocks of the plot are moved along
+// with its helpers, flags, votes, comments, snapshots and contest entries. The plot at from is reset and becomes
+// free.
+func (db *DB) MovePlot(tx *world.Tx, from, to Position) error {
+	p, err := db.Plot(from)
+	if err != nil {
+		return fmt.Errorf("move plot: %w", err)
+	}
+	records, err := db.plotRecords(from)
+	if err != nil {
+		return fmt.Errorf("move plot: %w", err)
+	}
+	batch := new(leveldb.Batch)
+	db.unindex(batch, from, p)
+	db.removePlotRecords(batch, from)
+	batch.Delete(from.Hash())
+	db.lowerCursor(batch, from)
+	if err := db.relocate(batch, from, to, p, records); err != nil {
+		return fmt.Errorf("move plot: %w", err)
+	}
+	if err := db.replacePlayerPlot(batch, p.Owner, from, to); err != nil {
+		return fmt.Errorf("move plot: %w", err)
+	}
+	contests, err := db.changeEntries(batch, map[Position]*Position{from: &to})
+	if err != nil {
+		return fmt.Errorf("move plot: %w", err)
+	}
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return fmt.Errorf("move plot: %w", err)
+	}
+	delete(db.cache, from)
+	db.cache[to] = p
+	db.cacheContests(contests)
+	reloadOwner(p.Owner)
+	_ = os.RemoveAll(db.snapshotDir(to))
+	if err := os.Rename(db.snapshotDir(from), db.snapshotDir(to)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("move plot: %w", err)
+	}
+
+	CaptureBuild(tx, from, db.settings).Place(tx, to, db.settings)
+	from.Reset(tx, db.settings)
+	from.SetBorder(tx, db.settings, db.settings.BoundaryBlock)
+	to.SetBorder(tx, db.settings, p.BorderBlock())
+	return nil
+}
+
+// SwapPlots swaps the plots at the Positions a and b, which must both be claimed. The blocks of the plots are
+// swapped along with their helpers, flags, votes, comments, snapshots and contest entries.
+func (db *DB) SwapPlots(tx *world.Tx, a, b Position) error {
+	pa, err := db.Plot(a)
+	if err != nil {
+		return fmt.Errorf("swap plots: %w", err)
+	}
+	pb, err := db.Plot(b)
+	if err != nil {
+		return fmt.Errorf("swap plots: %w", err)
+	}
+	recordsA, err := db.plotRecords(a)
+	if err != nil {
+		return fmt.Errorf("swap plots: %w", err)
+	}
+	recordsB, err := db.plotRecords(b)
+	if err != nil {
+		return fmt.Errorf("swap plots: %w", err)
+	}
+	batch := new(leveldb.Batch)
+	db.unindex(batch, a, pa)
+	db.unindex(batch, b, pb)
+	db.removePlotRecords(batch, a)
+	db.removePlotRecords(batch, b)
+	if err := db.relocate(batch, a, b, pa, recordsA); err != nil {
+		return fmt.Errorf("swap plots: %w", err)
+	}
+	if err := db.relocate(batch, b, a, pb, recordsB); err != nil {
+		return fmt.Errorf("swap plots: %w", err)
+	}
+	if pa.Owner != pb.Owner {
+		// If both plots have the same owner, the plots of the owner remain the same.
+		if err := db.replacePlayerPlot(batch, pa.Owner, a, b); err != nil {
+			return fmt.Errorf("swap plots: %w", err)
+		}
+		if err := db.replacePlayerPlot(batch, pb.Owner, b, a); err != nil {
+			return fmt.Errorf("swap plots: %w", err)
+		}
+	}
+	contests, err := db.changeEntries(batch, map[Position]*Position{a: &b, b: &a})
+	if err != nil {
+		return fmt.Errorf("swap plots: %w", err)
+	}
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return fmt.Errorf("swap plots: %w", err)
+	}
+	db.cache[a], db.cache[b] = pb, pa
+	db.cacheContests(contests)
+	reloadOwner(pa.Owner)
+	reloadOwner(pb.Owner)
+	if err := db.swapSnapshotDirs(a, b); err != nil {
+		return fmt.Errorf("swap plots: %w", err)
+	}
+
+	buildA, buildB := CaptureBuild(tx, a, db.settings), CaptureBuild(tx, b, db.settings)
+	buildA.Place(tx, b, db.settings)
+	buildB.Place(tx, a, db.settings)
+	a.SetBorder(tx, db.settings, pb.BorderBlock())
+	b.SetBorder(tx, db.settings, pa.BorderBlock())
+	return nil
+}
+
+// swapSnapshotDirs swaps the directories holding the snapshots of the plots at the Positions passed.
+func (db *DB) swapSnapshotDirs(a, b Position) error {
+	tmp := db.snapshotDir(a) + ".swap"
+	for _, r := range [][2]string{{db.snapshotDir(a), tmp}, {db.snapshotDir(b), db.snapshotDir(a)}, {tmp, db.snapshotDir(b)}} {
+		if err := os.Rename(r[0], r[1]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package plot
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+)
+
+// Role is the role that a player has on a Plot. The Role of a player determines which Permissions apply to
+// it when it interacts with the plot.
+type Role uint8
+
+const (
+	// RoleVisitor is the Role of any player that was not explicitly added to a Plot.
+	RoleVisitor Role = iota
+	// RoleHelper is the Role of players found in Plot.Helpers.
+	RoleHelper
+	// RoleTrusted is the Role of players found in Plot.Trusted.
+	RoleTrusted
+	// RoleOwner is the Role of the owner of a Plot. The owner always has all Permissions.
+	RoleOwner
+)
+
+// Roles returns all Roles that may have their Permissions changed by the owner of a Plot.
+func Roles() []Role {
+	return []Role{RoleVisitor, RoleHelper, RoleTrusted}
+}
+
+// String returns the name of the Role, such as 'visitor'.
+func (r Role) String() string {
+	switch r {
+	case RoleVisitor:
+		return "visitor"
+	case RoleHelper:
+		return "helper"
+	case RoleTrusted:
+		return "trusted"
+	case RoleOwner:
+		return "owner"
+	}
+	panic("should never happen")
+}
+
+// RoleByName looks up a Role by the name returned by Role.String. False is returned if no Role with the name
+// exists.
+func RoleByName(name string) (Role, bool) {
+	for _, r := range append(Roles(), RoleOwner) {
+		if strings.EqualFold(r.String(), name) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// Permission is a set of actions that players may perform on a Plot. Multiple Permissions may be combined
+// using a bitwise OR.
+type Permission uint16
+
+const (
+	// PermissionBuild allows breaking and placing blocks and using items on blocks.
+	PermissionBuild Permission = 1 << iota
+	// PermissionContainers allows opening containers such as chests, barrels and furnaces.
+	PermissionContainers
+	// PermissionDoors allows using doors, trapdoors, fence gates, buttons and levers.
+	PermissionDoors
+	// PermissionItemFrames allows placing items in and taking items out of item frames.
+	PermissionItemFrames
+	// PermissionAttack allows attacking entities.
+	PermissionAttack
+	// PermissionDrop allows dropping items.
+	PermissionDrop
+	// PermissionPickup allows picking up items.
+	PermissionPickup
+	// PermissionBuckets allows emptying and filling buckets.
+	PermissionBuckets
+
+	// PermissionAll holds all Permissions combined.
+	PermissionAll = PermissionBuild | PermissionContainers | PermissionDoors | PermissionItemFrames |
+		PermissionAttack | PermissionDrop | PermissionPickup | PermissionBuckets
+)
+
+// permissionNames holds the names of every single Permission, in the order they are listed in.
+var permissionNames = []struct {
+	perm Permission
+	name string
+}{
+	{PermissionBuild, "build"},
+	{PermissionContainers, "containers"},
+	{PermissionDoors, "doors"},
+	{PermissionItemFrames, "item_frames"},
+	{PermissionAttack, "attack"},
+	{PermissionDrop, "drop"},
+	{PermissionPickup, "pickup"},
+	{PermissionBuckets, "buckets"},
+}
+
+// Permissions returns a list of all single Permissions.
+func Permissions() []Permission {
+	perms := make([]Permission, 0, len(permissionNames))
+	for _, n := range permissionNames {
+		perms = append(perms, n.perm)
+	}
+	return perms
+}
+
+// String returns the name of a single Permission, such as 'build'.
+func (p Permission) String() string {
+	for _, n := range permissionNames {
+		if n.perm == p {
+			return n.name
+		}
+	}
+	return fmt.Sprintf("Permission(%d)", uint16(p))
+}
+
+// PermissionByName looks up a single Permission by the name returned by Permission.String. False is returned
+// if no Permission with the name exists.
+func PermissionByName(name string) (Permission, bool) {
+	for _, n := range permissionNames {
+		if strings.EqualFold(n.name, name) {
+			return n.perm, true
+		}
+	}
+	return 0, false
+}
+
+// DefaultPermissions returns the Permissions that a Role has on a Plot if the owner has not changed them.
+func DefaultPermissions(r Role) Permission {
+	switch r {
+	case RoleVisitor:
+		return PermissionDrop | PermissionPickup
+	default:
+		return PermissionAll
+	}
+}
+
+// Role returns the Role that the player with the UUID passed has on the Plot.
+func (p *Plot) Role(id uuid.UUID) Role {
+	switch {
+	case p.Owner == id:
+		return RoleOwner
+	case slices.Index(p.Trusted, id) != -1:
+		return RoleTrusted
+	case slices.Index(p.Helpers, id) != -1:
+		return RoleHelper
+	}
+	return RoleVisitor
+}
+
+// RolePermissions returns the Permissions that players with the Role passed have on the Plot.
+func (p *Plot) RolePermissions(r Role) Permission {
+	if r == RoleOwner {
+		return PermissionAll
+	}
+	if perm, ok := p.Permissions[r]; ok {
+		return perm
+	}
+	return DefaultPermissions(r)
+}
+
+// SetPermission allows or disallows a Permission for all players with the Role passed. The Permissions of the
+// owner cannot be changed.
+func (p *Plot) SetPermission(r Role, perm Permission, allowed bool) {
+	if r == RoleOwner {
+		return
+	}
+	current := p.RolePermissions(r)
+	if allowed {
+		current |= perm
+	} else {
+		current &^= perm
+	}
+	if p.Permissions == nil {
+		p.Permissions = map[Role]Permission{}
+	}
+	p.Permissions[r] = current
+}
+
+// Allowed checks if the player with the UUID passed has the Permission passed on the Plot.
+func (p *Plot) Allowed(id uuid.UUID, perm Permission) bool {
+	return p.RolePermissions(p.Role(id))&perm == perm
+}
+
+// SetRole changes the Role of the player with the UUID passed. Setting the Role to RoleVisitor removes the
+// player from all lists. The Role of the owner cannot be changed this way.
+func (p *Plot) SetRole(id uuid.UUID, r Role) {
+	if p.Owner == id || r == RoleOwner {
+		return
+	}
+	p.Helpers = slices.DeleteFunc(p.Helpers, func(other uuid.UUID) bool { return other == id })
+	p.Trusted = slices.DeleteFunc(p.Trusted, func(other uuid.UUID) bool { return other == id })
+	switch r {
+	case RoleHelper:
+		p.Helpers = append(p.Helpers, id)
+	case RoleTrusted:
+		p.Trusted = append(p.Trusted, id)
+	}
+}
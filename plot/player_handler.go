@@ -1,16 +1,21 @@
 package plot
 
 import (
+	"errors"
+	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/item"
 	"github.com/df-mc/dragonfly/server/player"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/dragonfly/server/world/particle"
 	"github.com/df-mc/dragonfly/server/world/sound"
+	"github.com/df-mc/goleveldb/leveldb"
 	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
-	"slices"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+	"strings"
 	"sync"
+	"time"
 )
 
 // PlayerHandler handles events of a player.Player. It handles things such as preventing players from placing
@@ -21,6 +26,38 @@ type PlayerHandler struct {
 	settings Settings
 	db       *DB
 	plots    []Position
+
+	// current is the Position of the plot that the player was last in. inPlot is true if the player is
+	// currently within the bounds of that plot.
+	current Position
+	inPlot  bool
+	// gameMode is the game mode that the player had before it was changed by the flags of the plot it is
+	// currently in. It is nil if the game mode was not changed.
+	gameMode world.GameMode
+	// transfer is the offer made to the player to transfer a plot to it.
+	transfer transferOffer
+	// visited holds the plots that the player visited since it joined, so that each plot counts at most one
+	// visit per session of the player.
+	visited map[Position]struct{}
+	// lastComment is the last time at which the player left a comment on a plot.
+	lastComment time.Time
+	// searchResults holds the positions of the plots found in the last search of the player.
+	searchResults []Position
+	// activity holds the times at which the player was last active.
+	activity Activity
+	// clear is the request of the player to clear a plot, which must be confirmed.
+	clear clearRequest
+	// undo is the last action of the player that may be undone.
+	undo undoAction
+	// inspecting is true if the player is inspecting the history of blocks it clicks instead of interacting
+	// with them.
+	inspecting bool
+	// selecting is true if the player is selecting a region with the wand instead of interacting with the
+	// blocks it clicks. selection holds the two corners of the region, each of which is only set if the
+	// corresponding value in selected is true.
+	selecting bool
+	selection [2]cube.Pos
+	selected  [2]bool
 }
 
 // LookupHandler looks up the PlayerHandler of a player.Player passed.
@@ -35,17 +72,55 @@ func LookupHandler(p *player.Player) (*PlayerHandler, bool) {
 // handlers holds a list of handlers that are currently open.
 var handlers sync.Map
 
+// Online checks if the player with the UUID passed currently has a PlayerHandler, meaning it is online.
+func Online(id uuid.UUID) bool {
+	_, ok := handlers.Load(id)
+	return ok
+}
+
 // NewPlayerHandler creates a new PlayerHandler for the player.Player passed. The Settings and DB are used to
 // track which plots the player.Player can build in.
-func NewPlayerHandler(id uuid.UUID, settings Settings, db *DB) *PlayerHandler {
+func NewPlayerHandler(p *player.Player, settings Settings, db *DB) *PlayerHandler {
+	id := p.UUID()
 	positions, _ := db.PlayerPlots(id)
+	_ = db.StorePlayerName(id, p.Name())
+	for _, pos := range positions {
+		pl, err := db.Plot(pos)
+		if err != nil {
+			continue
+		}
+		// Make sure the name of the owner in the plot is up-to-date in case the player changed its name.
+		if pl.OwnerName != p.Name() {
+			pl.OwnerName = p.Name()
+			_ = db.StorePlot(pos, pl)
+		}
+	}
 	h := &PlayerHandler{
 		id:       id,
 		settings: settings,
 		db:       db,
 		plots:    positions,
+		visited:  map[Position]struct{}{},
 	}
+	h.activity, _ = db.Activity(id)
+	h.activity.LastLogin = time.Now()
+	_ = db.StoreActivity(id, h.activity)
 	handlers.Store(id, h)
+
+	if inbox, err := db.Inbox(id); err == nil {
+		unread := 0
+		for _, c := range inbox {
+			if !c.Read {
+				unread++
+			}
+		}
+		if unread > 0 {
+			p.Message(text.Colourf("<yellow>You have %v unread comment(s) on your plots. Use /p inbox to read them.</yellow>", unread))
+		}
+	}
+	if archives, err := db.Archives(id, ArchiveExpired); err == nil && len(archives) > 0 {
+		p.Message(text.Colourf("<yellow>%v of your plots were archived. Use /p archive list to see them and /p archive restore to restore them.</yellow>", len(archives)))
+	}
 	return h
 }
 
@@ -83,71 +158,606 @@ func (h *PlayerHandler) SetPlotPositions(positions []Position) error {
 	return h.db.StorePlayerPlots(h.id, positions)
 }
 
-// HandleMove shows information on the plot that the player enters.
+// ReloadPlotPositions reads the positions of all plots that the PlayerHandler holds from the DB again. It
+// should be called after the plots of the player were changed without using SetPlotPositions.
+func (h *PlayerHandler) ReloadPlotPositions() error {
+	positions, err := h.db.PlayerPlots(h.id)
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return err
+	}
+	h.plots = positions
+	return nil
+}
+
+// SearchResults returns the positions of the plots found in the last search of the player, as set using
+// SetSearchResults.
+func (h *PlayerHandler) SearchResults() []Position {
+	return h.searchResults
+}
+
+// SetSearchResults sets the positions of the plots found in the last search of the player, so that the player
+// may visit them by their number in the results.
+func (h *PlayerHandler) SetSearchResults(positions []Position) {
+	h.searchResults = positions
+}
+
+// MaximumPlots returns the maximum amount of plots that the player may claim. This is Settings.MaximumPlots
+// plus Settings.ApprovalBonus for every plot that the player currently owns that was approved by staff.
+func (h *PlayerHandler) MaximumPlots() int {
+	approved := 0
+	for _, p := range h.Plots() {
+		if p.Review == ReviewApproved {
+			approved++
+		}
+	}
+	return h.settings.MaximumPlots + h.settings.ApprovalBonus*approved
+}
+
+// commentCooldown is the minimum time between two comments left by the same player.
+const commentCooldown = time.Second * 30
+
+// AllowComment checks if the player may leave a comment on a plot, which is only allowed once every
+// commentCooldown. If allowed, the cooldown starts again. If not, the time until the player may leave a
+// comment again is returned.
+func (h *PlayerHandler) AllowComment() (time.Duration, bool) {
+	if remaining := time.Until(h.lastComment.Add(commentCooldown)); remaining > 0 {
+		return remaining, false
+	}
+	h.lastComment = time.Now()
+	return 0, true
+}
+
+// transferTimeout is the time after which an offer to transfer a plot expires.
+const transferTimeout = time.Minute
+
+// OfferTransfer offers the player of the PlayerHandler to transfer the plot at the Position passed from the
+// player with the UUID passed. Any previous offer is replaced. The offer expires after a minute.
+func (h *PlayerHandler) OfferTransfer(pos Position, from uuid.UUID) {
+	h.transfer = transferOffer{pos: pos, from: from, expiration: time.Now().Add(transferTimeout)}
+}
+
+// TransferOffer returns the Position of the plot offered to the player using OfferTransfer and the UUID of
+// the player that offered it. False is returned if no offer was made or if it expired. The offer is removed
+// when calling this method.
+func (h *PlayerHandler) TransferOffer() (Position, uuid.UUID, bool) {
+	offer := h.transfer
+	h.transfer = transferOffer{}
+	if offer.from == uuid.Nil || time.Now().After(offer.expiration) {
+		return Position{}, uuid.Nil, false
+	}
+	return offer.pos, offer.from, true
+}
+
+// transferOffer is an offer to transfer a plot to another player.
+type transferOffer struct {
+	pos        Position
+	from       uuid.UUID
+	expiration time.Time
+}
+
+// clearTimeout is the time within which a request to clear a plot must be confirmed.
+const clearTimeout = time.Second * 30
+
+// RequestClear records a request of the player to clear the plot at the Position passed. Any previous request
+// is replaced. The request must be confirmed within 30 seconds.
+func (h *PlayerHandler) RequestClear(pos Position) {
+	h.clear = clearRequest{pos: pos, expiration: time.Now().Add(clearTimeout)}
+}
+
+// ClearRequest returns the Position of the plot that the player requested to clear using RequestClear. False
+// is returned if no request was made or if it expired. The request is removed when calling this method.
+func (h *PlayerHandler) ClearRequest() (Position, bool) {
+	req := h.clear
+	h.clear = clearRequest{}
+	if req.expiration.IsZero() || time.Now().After(req.expiration) {
+		return Position{}, false
+	}
+	return req.pos, true
+}
+
+// clearRequest is a request to clear a plot.
+type clearRequest struct {
+	pos        Position
+	expiration time.Time
+}
+
+// SetUndo records an action of the player, such as 'clear', that may be undone by calling the function passed
+// within Settings.UndoWindow. Any action recorded previously can no longer be undone. If Settings.UndoWindow is
+// 0, SetUndo does nothing.
+func (h *PlayerHandler) SetUndo(action string, f func(tx *world.Tx) error) {
+	if h.settings.UndoWindow <= 0 {
+		return
+	}
+	h.undo = undoAction{action: action, f: f, expiration: time.Now().Add(h.settings.UndoWindow)}
+}
+
+// Undo returns the name of the last action recorded using SetUndo and the function that undoes it. False is
+// returned if no action was recorded or if it may no longer be undone. The action is removed when calling this
+// method.
+func (h *PlayerHandler) Undo() (string, func(tx *world.Tx) error, bool) {
+	u := h.undo
+	h.undo = undoAction{}
+	if u.f == nil || time.Now().After(u.expiration) {
+		return "", nil, false
+	}
+	return u.action, u.f, true
+}
+
+// undoAction is an action of a player that may be undone.
+type undoAction struct {
+	action     string
+	f          func(tx *world.Tx) error
+	expiration time.Time
+}
+
+// ToggleInspect turns inspecting blocks on or off. While inspecting, the history of every block in a plot that
+// the player clicks is shown instead of interacting with the block. True is returned if the player is now
+// inspecting.
+func (h *PlayerHandler) ToggleInspect() bool {
+	h.inspecting = !h.inspecting
+	return h.inspecting
+}
+
+// ToggleWand turns the selection wand on or off. While the wand is on, breaking a block sets the first corner
+// of the selection and using an item on a block sets the second corner, instead of interacting with the block.
+// True is returned if the wand is now on.
+func (h *PlayerHandler) ToggleWand() bool {
+	h.selecting = !h.selecting
+	return h.selecting
+}
+
+// Selection returns the lowest and highest corner of the region selected by the player using the wand. False
+// is returned if the player has not selected both corners.
+func (h *PlayerHandler) Selection() (low, high cube.Pos, ok bool) {
+	if !h.selected[0] || !h.selected[1] {
+		return cube.Pos{}, cube.Pos{}, false
+	}
+	a, b := h.selection[0], h.selection[1]
+	low = cube.Pos{min(a[0], b[0]), min(a[1], b[1]), min(a[2], b[2])}
+	high = cube.Pos{max(a[0], b[0]), max(a[1], b[1]), max(a[2], b[2])}
+	return low, high, true
+}
+
+// selectCorner sets the corner of the selection with the index passed to the cube.Pos passed.
+func (h *PlayerHandler) selectCorner(p *player.Player, corner int, pos cube.Pos) {
+	h.selection[corner], h.selected[corner] = pos, true
+	msg := text.Colourf("<green>Corner %v set to %v %v %v.</green>", corner+1, pos[0], pos[1], pos[2])
+	if low, high, ok := h.Selection(); ok {
+		msg += text.Colourf(" <grey>(%vx%vx%v)</grey>", high[0]-low[0]+1, high[1]-low[1]+1, high[2]-low[2]+1)
+	}
+	p.Message(msg)
+}
+
+// inspectLimit is the maximum amount of changes shown when inspecting a block.
+const inspectLimit = 8
+
+// inspect shows the history of the block at the cube.Pos passed to the player. Only the owner and trusted
+// players of the plot that the block is in, and admins, may inspect blocks.
+func (h *PlayerHandler) inspect(p *player.Player, pos cube.Pos) {
+	plotPos := PosFromBlockPos(pos, h.settings)
+	pl, err := h.db.Plot(plotPos)
+	if err != nil || (pl.Role(h.id) < RoleTrusted && !h.settings.Admin(p.UUID())) {
+		p.Message(text.Colourf("<red>You cannot inspect blocks in this plot.</red>"))
+		return
+	}
+	history, err := h.db.BlockHistory(pos, inspectLimit)
+	if err != nil {
+		p.Message(text.Colourf("<red>Failed reading block history, please try again later. (%v)</red>", err))
+		return
+	}
+	if len(history) == 0 {
+		p.Message(text.Colourf("<yellow>No changes were logged for the block at %v %v %v.</yellow>", pos[0], pos[1], pos[2]))
+		return
+	}
+	var str strings.Builder
+	for _, c := range history {
+		name, err := h.db.PlayerName(c.Actor)
+		if err != nil {
+			name = c.Actor.String()
+		}
+		str.WriteString(text.Colourf("\n<white>%v</white> <grey>%v at %v</grey>", name, c.Description(), c.Time.Format("2006-01-02 15:04:05")))
+	}
+	p.Message(text.Colourf("<green>History of the block at %v %v %v:</green>", pos[0], pos[1], pos[2]) + str.String())
+}
+
+// HandleMove shows information on the plot that the player enters and applies the flags of the plot. Players
+// are prevented from entering plots that they are denied from.
 func (h *PlayerHandler) HandleMove(ctx *player.Context, pos mgl64.Vec3, _ cube.Rotation) {
-	p := ctx.V()
-	newPos, oldPos := cube.PosFromVec3(pos), cube.PosFromVec3(p.Position())
-	plotPos := PosFromBlockPos(newPos, h.settings)
-	previous := PosFromBlockPos(oldPos, h.settings)
+	h.move(ctx, pos)
+}
+
+// HandleTeleport shows information on the plot that the player teleports into and applies the flags of the
+// plot. Players are prevented from teleporting into plots that they are denied from.
+func (h *PlayerHandler) HandleTeleport(ctx *player.Context, pos mgl64.Vec3) {
+	h.move(ctx, pos)
+}
 
+// move handles the movement of the player.Player to a new position. If the player leaves or enters a plot with
+// this movement, the flags of the plots are removed or applied.
+func (h *PlayerHandler) move(ctx *player.Context, pos mgl64.Vec3) {
+	p := ctx.V()
+	blockPos := cube.PosFromVec3(pos)
+	plotPos := PosFromBlockPos(blockPos, h.settings)
 	min, max := plotPos.Bounds(h.settings)
-	if (plotPos != previous && Within(newPos, min, max)) ||
-		(plotPos == previous && (!Within(oldPos, min, max) && Within(newPos, min, max))) {
-		// Player entered a plot that it wasn't in before.
-		pl, err := h.db.Plot(plotPos)
-		if err != nil {
-			pl = &Plot{}
+
+	inPlot := Within(blockPos, min, max)
+	if inPlot == h.inPlot && (!inPlot || plotPos == h.current) {
+		return
+	}
+	if inPlot {
+		if pl, err := h.db.Plot(plotPos); err == nil && pl.Denies(h.id) {
+			p.SendTip(text.Colourf("<red>You are denied from entering this plot.</red>"))
+			ctx.Cancel()
+			return
+		}
+	}
+	if h.inPlot {
+		h.leave(p, h.current)
+	}
+	h.current, h.inPlot = plotPos, inPlot
+	if inPlot {
+		h.enter(p, plotPos)
+	}
+}
+
+// enter is called when the player.Player passed enters the plot at the Position passed.
+func (h *PlayerHandler) enter(p *player.Player, pos Position) {
+	pl, err := h.db.Plot(pos)
+	if err != nil {
+		pl = &Plot{}
+	} else if _, ok := h.visited[pos]; !ok && pl.Owner != h.id {
+		h.visited[pos] = struct{}{}
+		_ = h.db.RecordVisit(pos, pl)
+	}
+	p.SendTip(pl.Info())
+	if msg, ok := FlagGreeting.Value(pl); ok {
+		p.Message(text.Colourf("<%v>■</%v> <white>%v</white>", pl.ColourToFormat(), pl.ColourToFormat(), msg))
+	}
+	h.applyFlags(p, pl)
+}
+
+// leave is called when the player.Player passed leaves the plot at the Position passed.
+func (h *PlayerHandler) leave(p *player.Player, pos Position) {
+	if pl, err := h.db.Plot(pos); err == nil {
+		if msg, ok := FlagFarewell.Value(pl); ok {
+			p.Message(text.Colourf("<%v>■</%v> <white>%v</white>", pl.ColourToFormat(), pl.ColourToFormat(), msg))
 		}
-		p.SendTip(pl.Info())
+	}
+	h.resetFlags(p)
+}
+
+// ApplyFlags re-applies the flags of the plot that the player.Player passed is currently in. It should be
+// called after the flags of the plot were changed.
+func (h *PlayerHandler) ApplyFlags(p *player.Player) {
+	if !h.inPlot {
+		return
+	}
+	h.resetFlags(p)
+	if pl, err := h.db.Plot(h.current); err == nil {
+		h.applyFlags(p, pl)
 	}
 }
 
-// HandleBlockBreak prevents block breaking outside of the player's plots.
+// applyFlags applies the flags of the Plot passed that change the state of the player.Player passed.
+func (h *PlayerHandler) applyFlags(p *player.Player, pl *Plot) {
+	mode := p.GameMode()
+	if name, ok := FlagGameMode.Value(pl); ok && pl.Role(h.id) == RoleVisitor {
+		mode = gameModes[name]
+	}
+	if fly, ok := FlagFly.Value(pl); ok {
+		mode = flightGameMode{GameMode: mode, fly: fly}
+	}
+	if mode != p.GameMode() {
+		h.gameMode = p.GameMode()
+		p.SetGameMode(mode)
+		if !mode.AllowsFlying() {
+			p.StopFlying()
+		}
+	}
+	if c, ok := lookupConn(h.id); ok {
+		if t, ok := FlagTime.Value(pl); ok {
+			c.setTime(&t, t)
+		}
+		if w, ok := FlagWeather.Value(pl); ok {
+			c.setWeather(&w, w)
+		}
+	}
+}
+
+// resetFlags resets the state of the player.Player passed that was changed by the flags of a plot.
+func (h *PlayerHandler) resetFlags(p *player.Player) {
+	if h.gameMode != nil {
+		p.SetGameMode(h.gameMode)
+		h.gameMode = nil
+	}
+	if c, ok := lookupConn(h.id); ok {
+		pos := cube.PosFromVec3(p.Position())
+		weather := WeatherClear
+		if p.Tx().ThunderingAt(pos) {
+			weather = WeatherThunder
+		} else if p.Tx().RainingAt(pos) {
+			weather = WeatherRain
+		}
+		c.setTime(nil, p.Tx().World().Time())
+		c.setWeather(nil, weather)
+	}
+}
+
+// flightGameMode wraps around a world.GameMode to change if players with the game mode may fly.
+type flightGameMode struct {
+	world.GameMode
+	fly bool
+}
+
+// AllowsFlying ...
+func (m flightGameMode) AllowsFlying() bool {
+	return m.fly
+}
+
+// HandleBlockBreak prevents block breaking outside of the player's plots and logs the blocks broken. While
+// inspecting, the history of the block is shown instead, and while using the wand, the first corner of the
+// selection is set.
 func (h *PlayerHandler) HandleBlockBreak(ctx *player.Context, pos cube.Pos, _ *[]item.Stack, _ *int) {
-	p := ctx.V()
-	if !h.canEdit(pos) {
-		p.Tx().PlaySound(pos.Vec3Centre(), sound.Deny{})
-		p.Tx().AddParticle(pos.Vec3Centre(), particle.BlockForceField{})
+	if h.inspecting {
+		ctx.Cancel()
+		h.inspect(ctx.V(), pos)
+		return
+	}
+	if h.selecting {
 		ctx.Cancel()
+		h.selectCorner(ctx.V(), 0, pos)
+		return
+	}
+	if !h.CanEdit(pos) {
+		h.deny(ctx, pos)
+		return
 	}
+	h.edited(pos)
+	h.logChange(pos, blockAt(ctx.V().Tx(), pos), block.Air{})
 }
 
-// HandleBlockPlace prevents block placing outside of the player's plots.
-func (h *PlayerHandler) HandleBlockPlace(ctx *player.Context, pos cube.Pos, _ world.Block) {
-	p := ctx.V()
-	if !h.canEdit(pos) {
-		p.Tx().PlaySound(pos.Vec3Centre(), sound.Deny{})
-		p.Tx().AddParticle(pos.Vec3Centre(), particle.BlockForceField{})
-		ctx.Cancel()
+// HandleBlockPlace prevents block placing outside of the player's plots and logs the blocks placed.
+func (h *PlayerHandler) HandleBlockPlace(ctx *player.Context, pos cube.Pos, b world.Block) {
+	if !h.CanEdit(pos) {
+		h.deny(ctx, pos)
+		return
 	}
+	h.edited(pos)
+	h.logChange(pos, blockAt(ctx.V().Tx(), pos), b)
+}
+
+// logChange logs the change of the block at the cube.Pos passed from one block to another by the player.
+func (h *PlayerHandler) logChange(pos cube.Pos, old, new world.Block) {
+	_ = h.db.LogBlockChange(PosFromBlockPos(pos, h.settings), BlockChange{Actor: h.id, Pos: pos, Old: old, New: new, Time: time.Now()})
 }
 
-// HandleItemUseOnBlock prevents using items on blocks outside of the player's plots.
+// logBucketUse logs the change that using the item.Bucket passed on the block at the cube.Pos passed makes,
+// following the logic of item.Bucket.UseOnBlock. The bucket is used after the handler is called, so the change
+// is determined in advance rather than by comparing the blocks afterwards. Liquids placed in blocks that are
+// waterlogged are not logged, as the block itself does not change.
+func (h *PlayerHandler) logBucketUse(tx *world.Tx, bucket item.Bucket, pos cube.Pos, face cube.Face) {
+	if bucket.Empty() {
+		if liq, ok := tx.Liquid(pos); ok && liq.LiquidDepth() == 8 && !liq.LiquidFalling() {
+			if _, ok := tx.Block(pos).(block.Air); ok {
+				h.logChange(pos, liq, block.Air{})
+			}
+		}
+		return
+	}
+	liq, ok := bucket.Content.Liquid()
+	if !ok {
+		return
+	}
+	liq = liq.WithDepth(8, false)
+	for _, target := range []cube.Pos{pos, pos.Side(face)} {
+		b := tx.Block(target)
+		if d, ok := b.(world.LiquidDisplacer); ok && d.CanDisplace(liq) {
+			return
+		}
+		if r, ok := b.(block.Replaceable); ok && r.ReplaceableBy(liq) {
+			if old := blockAt(tx, target); world.BlockRuntimeID(old) != world.BlockRuntimeID(liq) {
+				h.logChange(target, old, liq)
+			}
+			return
+		}
+	}
+}
+
+// blockAt returns the block at the cube.Pos passed. If the block is air, the liquid at the position is
+// returned if present.
+func blockAt(tx *world.Tx, pos cube.Pos) world.Block {
+	b := tx.Block(pos)
+	if _, ok := b.(block.Air); ok {
+		if liq, ok := tx.Liquid(pos); ok {
+			return liq
+		}
+	}
+	return b
+}
+
+// edited updates the time of the last edit of the plot that the cube.Pos passed is in. To prevent storing
+// the plot with every edit, the time is only updated if the last edit was over a minute ago.
+func (h *PlayerHandler) edited(pos cube.Pos) {
+	if time.Since(h.activity.LastEdit) >= time.Minute {
+		h.activity.LastEdit = time.Now()
+		_ = h.db.StoreActivity(h.id, h.activity)
+	}
+	plotPos := PosFromBlockPos(pos, h.settings)
+	pl, err := h.db.Plot(plotPos)
+	if err != nil || time.Since(pl.LastEdit) < time.Minute {
+		return
+	}
+	pl.LastEdit = time.Now()
+	_ = h.db.StorePlot(plotPos, pl)
+}
+
+// HandleStartBreak prevents punching item frames without the PermissionItemFrames.
+func (h *PlayerHandler) HandleStartBreak(ctx *player.Context, pos cube.Pos) {
+	if _, ok := ctx.V().Tx().Block(pos).(block.ItemFrame); ok && !h.allowed(pos, PermissionItemFrames) {
+		h.deny(ctx, pos)
+	}
+}
+
+// HandleItemUseOnBlock prevents using items on blocks and activating blocks if the player does not have the
+// Permission required to do so. The changes made using buckets are logged. While using the wand, the second
+// corner of the selection is set instead.
 func (h *PlayerHandler) HandleItemUseOnBlock(ctx *player.Context, pos cube.Pos, face cube.Face, _ mgl64.Vec3) {
 	p := ctx.V()
+	if h.inspecting {
+		ctx.Cancel()
+		h.inspect(p, pos)
+		return
+	}
+	if h.selecting {
+		ctx.Cancel()
+		h.selectCorner(p, 1, pos)
+		return
+	}
 	held, _ := p.HeldItems()
-	if _, ok := held.Item().(world.Block); !ok && (!h.canEdit(pos) || !h.canEdit(pos.Side(face))) {
+	if b, ok := p.Tx().Block(pos).(block.Activatable); ok && (!p.Sneaking() || held.Empty()) {
+		// Activating a block has precedence over using the item held, so the permission of the block is the
+		// one that counts.
+		if !h.allowed(pos, activationPermission(b)) {
+			h.deny(ctx, pos)
+		}
+		return
+	}
+	switch it := held.Item().(type) {
+	case world.Block:
 		// For blocks, we don't return here but at HandleBlockPlace.
+	case item.Bucket:
+		if h.locked(pos) || h.locked(pos.Side(face)) || !h.allowed(pos, PermissionBuckets) || !h.allowed(pos.Side(face), PermissionBuckets) {
+			ctx.Cancel()
+			return
+		}
+		h.logBucketUse(p.Tx(), it, pos, face)
+	default:
+		if !h.CanEdit(pos) || !h.CanEdit(pos.Side(face)) {
+			ctx.Cancel()
+		}
+	}
+}
+
+// HandleSignEdit prevents editing signs outside of the player's plots.
+func (h *PlayerHandler) HandleSignEdit(ctx *player.Context, pos cube.Pos, _ bool, _, _ string) {
+	if !h.CanEdit(pos) {
+		ctx.Cancel()
+	}
+}
+
+// HandleLecternPageTurn prevents turning pages of lecterns outside of the player's plots.
+func (h *PlayerHandler) HandleLecternPageTurn(ctx *player.Context, pos cube.Pos, _ int, _ *int) {
+	if !h.CanEdit(pos) {
+		ctx.Cancel()
+	}
+}
+
+// HandleAttackEntity prevents attacking entities without the PermissionAttack and attacking players in plots
+// without the FlagPvP.
+func (h *PlayerHandler) HandleAttackEntity(ctx *player.Context, e world.Entity, _, _ *float64, _ *bool) {
+	if _, ok := e.(*player.Player); ok {
+		if !h.pvp(cube.PosFromVec3(e.Position())) {
+			ctx.Cancel()
+		}
+		return
+	}
+	if !h.allowed(cube.PosFromVec3(e.Position()), PermissionAttack) {
+		ctx.Cancel()
+	}
+}
+
+// HandleItemDrop prevents dropping items without the PermissionDrop or in plots where the FlagItemDrop is
+// disabled.
+func (h *PlayerHandler) HandleItemDrop(ctx *player.Context, _ item.Stack) {
+	pos := cube.PosFromVec3(ctx.V().Position())
+	if !h.allowed(pos, PermissionDrop) {
+		ctx.Cancel()
+		return
+	}
+	if pl, err := h.db.Plot(PosFromBlockPos(pos, h.settings)); err == nil {
+		if drop, ok := FlagItemDrop.Value(pl); ok && !drop {
+			ctx.Cancel()
+		}
+	}
+}
+
+// HandleItemPickup prevents picking up items without the PermissionPickup.
+func (h *PlayerHandler) HandleItemPickup(ctx *player.Context, _ *item.Stack) {
+	if !h.allowed(cube.PosFromVec3(ctx.V().Position()), PermissionPickup) {
 		ctx.Cancel()
 	}
 }
 
-// canEdit checks if the player.Player held by the PlayerHandler is permitted to edit the block at the
-// cube.Pos passed.
-func (h *PlayerHandler) canEdit(pos cube.Pos) bool {
+// deny cancels the context passed and shows the player that it is not allowed to interact with the block
+// at the cube.Pos passed.
+func (h *PlayerHandler) deny(ctx *player.Context, pos cube.Pos) {
+	p := ctx.V()
+	p.Tx().PlaySound(pos.Vec3Centre(), sound.Deny{})
+	p.Tx().AddParticle(pos.Vec3Centre(), particle.BlockForceField{})
+	ctx.Cancel()
+}
+
+// CanEdit checks if the player.Player held by the PlayerHandler is permitted to edit the block at the
+// cube.Pos passed. Nobody may edit plots that are done or that were submitted to a contest that is open for
+// submissions.
+func (h *PlayerHandler) CanEdit(pos cube.Pos) bool {
+	return !h.locked(pos) && h.allowed(pos, PermissionBuild)
+}
+
+// locked checks if the plot that the cube.Pos passed is in is locked for edits by everyone. See DB.Locked.
+func (h *PlayerHandler) locked(pos cube.Pos) bool {
+	return h.db.Locked(PosFromBlockPos(pos, h.settings))
+}
+
+// allowed checks if the player.Player held by the PlayerHandler has the Permission passed at the cube.Pos
+// passed. Outside of plots, only dropping and picking up items and attacking entities is allowed.
+func (h *PlayerHandler) allowed(pos cube.Pos, perm Permission) bool {
 	plotPos := PosFromBlockPos(pos, h.settings)
 	min, max := plotPos.Bounds(h.settings)
 	if !Within(pos, min, max) {
-		return false
+		return perm&(PermissionDrop|PermissionPickup|PermissionAttack) == perm
+	}
+	plot, err := h.db.Plot(plotPos)
+	if err != nil {
+		// Nobody may build in plots that are not claimed, but visitors may still do what they can do in any
+		// other plot.
+		return DefaultPermissions(RoleVisitor)&perm == perm
+	}
+	return plot.Allowed(h.id, perm)
+}
+
+// pvp checks if players may be attacked at the cube.Pos passed. Players may always be attacked outside of
+// plots, but only in plots with the FlagPvP enabled.
+func (h *PlayerHandler) pvp(pos cube.Pos) bool {
+	plotPos := PosFromBlockPos(pos, h.settings)
+	min, max := plotPos.Bounds(h.settings)
+	if !Within(pos, min, max) {
+		return true
 	}
 	plot, err := h.db.Plot(plotPos)
 	if err != nil {
 		return false
 	}
-	return plot.Owner == h.id || slices.Index(plot.Helpers, h.id) != -1
+	pvp, _ := FlagPvP.Value(plot)
+	return pvp
+}
+
+// activationPermission returns the Permission required to activate the block.Activatable passed.
+func activationPermission(b block.Activatable) Permission {
+	switch b.(type) {
+	case block.Container, block.EnderChest:
+		return PermissionContainers
+	case block.WoodDoor, block.WoodTrapdoor, block.WoodFenceGate:
+		return PermissionDoors
+	case block.ItemFrame:
+		return PermissionItemFrames
+	}
+	return PermissionBuild
 }
 
-// HandleQuit removes the PlayerHandler from the Handlers map.
+// HandleQuit removes the PlayerHandler from the Handlers map and records the time at which the player left.
 func (h *PlayerHandler) HandleQuit() {
+	h.activity.LastLogin = time.Now()
+	_ = h.db.StoreActivity(h.id, h.activity)
 	handlers.Delete(h.id)
 }
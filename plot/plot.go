@@ -1,12 +1,19 @@
 package plot
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl64"
 	"github.com/google/uuid"
 	"github.com/sandertv/gophertunnel/minecraft/text"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 )
 
 // Plot represents a plot in the world. Each plot has an owner
@@ -16,14 +23,67 @@ type Plot struct {
 	Owner uuid.UUID
 	// OwnerName is the name last recorded for the owner.
 	OwnerName string
+	// Number is the number of the plot among the plots of its owner, starting at 1. Unlike the index of the
+	// plot in the list of plots of the owner, it does not change when other plots of the owner are deleted.
+	// Numbers of deleted plots are reused by new plots. See DB.FreeNumber.
+	Number int `json:",omitempty"`
 	// Helpers is a list of helpers added to the plot. These helpers may edit the plot, but are unable to, for
 	// example, add other helpers.
 	Helpers []uuid.UUID
+	// Trusted is a list of trusted players added to the plot. By default, trusted players have the same
+	// permissions as helpers, but the owner may grant them more than helpers.
+	Trusted []uuid.UUID
+	// Denied is a list of players denied from the plot. These players cannot enter or visit the plot.
+	Denied []uuid.UUID `json:",omitempty"`
+	// Permissions holds the Permissions of each Role on the plot that were changed by the owner. Roles not
+	// present in the map have their DefaultPermissions.
+	Permissions map[Role]Permission
+	// Flags holds the values of all Flags set on the plot, indexed by the name of the Flag. The values are
+	// typically read using TypedFlag.Value.
+	Flags map[string]json.RawMessage
 	// Colour is the colour of the plot. The border of the plot will have this colour and the colour will be
 	// used to refer to different chunks owned by the player.
 	Colour string
+	// Alias is a name of the plot that is unique across the server. It may be used to teleport to the plot.
+	// The alias must be changed using DB.SetAlias so that it remains unique.
+	Alias string `json:",omitempty"`
+	// Tags is a list of categories of the plot, such as 'medieval' or 'pixel-art', that players may search
+	// for. Tags must be changed using DB.AddTag and DB.RemoveTag so that the plot remains indexed.
+	Tags []string `json:",omitempty"`
+	// Title is a short title of the plot, shown to players entering it.
+	Title string `json:",omitempty"`
+	// Description is a description of the plot, shown to players entering it. It may span multiple lines.
+	Description string `json:",omitempty"`
+
+	// Home is a custom position set by the owner that players teleporting to the plot are teleported to. If
+	// nil, players are teleported to the road at the corner of the plot.
+	Home *mgl64.Vec3 `json:",omitempty"`
+	// Claimed is the time at which the plot was claimed by its current owner.
+	Claimed time.Time
+	// LastEdit is the last time at which a block in the plot was changed by a player. It is updated at most
+	// once every minute.
+	LastEdit time.Time
+
+	// Completed is the time at which the plot was marked as done using DB.Complete. It is zero if the plot is
+	// not done. See Plot.Done.
+	Completed time.Time
+	// Review is the ReviewStatus of the plot after it was completed, and ReviewNote is the note left by the
+	// staff member that reviewed it, such as the reason the plot was rejected.
+	Review     ReviewStatus `json:",omitempty"`
+	ReviewNote string       `json:",omitempty"`
+
+	// Likes is the amount of players that liked the plot using DB.Like.
+	Likes int `json:",omitempty"`
+	// Ratings is the amount of players that rated the plot using DB.Rate, and RatingSum is the sum of all of
+	// those ratings. See Plot.Rating.
+	Ratings, RatingSum int `json:",omitempty"`
+	// Visits is the amount of times players other than the owner entered the plot.
+	Visits int `json:",omitempty"`
 
 	MergedDirections []cube.Direction
+
+	// AuditTrail holds a list of changes made to the ownership of the plot, ordered from oldest to newest.
+	AuditTrail []AuditEntry
 }
 
 // Owned checks if the Plot is currently owned.
@@ -31,13 +91,63 @@ func (p *Plot) Owned() bool {
 	return p.Owner != uuid.UUID{}
 }
 
+// Clone returns a copy of the Plot that may be changed without changing the Plot itself. Plots returned by
+// DB.Plot are shared, so they must be cloned before they are changed in ways that may fail to be stored.
+func (p *Plot) Clone() *Plot {
+	c := *p
+	c.Helpers, c.Trusted, c.Denied = slices.Clone(p.Helpers), slices.Clone(p.Trusted), slices.Clone(p.Denied)
+	c.Permissions, c.Flags = maps.Clone(p.Permissions), maps.Clone(p.Flags)
+	c.Tags, c.MergedDirections, c.AuditTrail = slices.Clone(p.Tags), slices.Clone(p.MergedDirections), slices.Clone(p.AuditTrail)
+	if p.Home != nil {
+		home := *p.Home
+		c.Home = &home
+	}
+	return &c
+}
+
+// SpawnPosition returns the position that players teleporting to the Plot at the Position passed should be
+// teleported to. This is the Home of the plot if set and still safe, or Position.TeleportPosition otherwise.
+func (p *Plot) SpawnPosition(tx *world.Tx, pos Position, settings Settings) mgl64.Vec3 {
+	if p.Home != nil {
+		min, max := pos.Bounds(settings)
+		if Within(cube.PosFromVec3(*p.Home), min, max) && SafeHome(tx, *p.Home) {
+			return *p.Home
+		}
+	}
+	return pos.TeleportPosition(tx, settings)
+}
+
+// Denies checks if the player with the UUID passed is denied from entering the Plot.
+func (p *Plot) Denies(id uuid.UUID) bool {
+	return slices.Index(p.Denied, id) != -1
+}
+
 // Info returns a string of info about the Plot.
 func (p *Plot) Info() string {
 	if !p.Owned() {
 		return text.Colourf("<white> This plot is currently <green>free</green>.</white>\n<white>   Use <green>/p claim</green> to claim it.")
 	}
 	c := p.ColourToFormat()
-	return text.Colourf("<%v>■</%v> <white>Now entering <green>%v</green>'s plot.", c, c, p.OwnerName)
+	info := text.Colourf("<%v>■</%v> <white>Now entering <green>%v</green>'s plot.</white>", c, c, p.OwnerName)
+	if p.Title != "" {
+		info = text.Colourf("<%v>■</%v> <white>Now entering <green>%v</green> by <green>%v</green>.</white>", c, c, p.Title, p.OwnerName)
+	}
+	if p.Description != "" {
+		info += text.Colourf("\n<grey>%v</grey>", p.Description)
+	}
+	return info
+}
+
+// Name returns a name that may be used to refer to the Plot. It is the title of the plot if set, or its
+// alias or colour otherwise.
+func (p *Plot) Name() string {
+	switch {
+	case p.Title != "":
+		return p.Title
+	case p.Alias != "":
+		return p.Alias
+	}
+	return p.ColourToString()
 }
 
 // ColourToFormat converts the colour of the plot to a text.FormatFunc and returns it.
@@ -118,6 +228,16 @@ func colourFromString(s string) (interface{}, error) {
 	return nil, fmt.Errorf("unexpected colour '%v'", s)
 }
 
+// BorderBlock returns the block that the border of the Plot is made of, which is concrete with the colour of
+// the plot.
+func (p *Plot) BorderBlock() world.Block {
+	c, err := colourFromString(p.Colour)
+	if err != nil {
+		return block.Concrete{}
+	}
+	return block.Concrete{Colour: c.(item.Colour)}
+}
+
 // ColourToString converts the colour of the plot to a readable representation.
 func (p *Plot) ColourToString() string {
 	return strings.Title(strings.Replace(p.Colour, "_", " ", -1))
@@ -1,10 +1,13 @@
 package plot
 
 import (
+	"fmt"
 	"github.com/df-mc/dragonfly/server/block"
 	"github.com/df-mc/dragonfly/server/block/cube"
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/go-gl/mathgl/mgl64"
+	"strconv"
+	"strings"
 )
 
 // Position represents the position of a plot. These positions are similar to chunk positions, in that they
@@ -27,6 +30,28 @@ func PosFromBlockPos(pos cube.Pos, settings Settings) Position {
 	return Position{pos[0] / fullPlotSize, pos[2] / fullPlotSize}
 }
 
+// ParsePosition parses a Position from a string in the format returned by Position.String, such as '3;-2'.
+func ParsePosition(s string) (Position, error) {
+	a, b, ok := strings.Cut(s, ";")
+	if !ok {
+		return Position{}, fmt.Errorf("parse position: expected format 'x;z', got '%v'", s)
+	}
+	x, err := strconv.Atoi(strings.TrimSpace(a))
+	if err != nil {
+		return Position{}, fmt.Errorf("parse position: %w", err)
+	}
+	z, err := strconv.Atoi(strings.TrimSpace(b))
+	if err != nil {
+		return Position{}, fmt.Errorf("parse position: %w", err)
+	}
+	return Position{x, z}, nil
+}
+
+// String returns the Position as a string in the format 'x;z', which may be parsed again using ParsePosition.
+func (pos Position) String() string {
+	return strconv.Itoa(pos[0]) + ";" + strconv.Itoa(pos[1])
+}
+
 // Add adds a Position to the current Position and returns a new resulting Position.
 func (pos Position) Add(p Position) Position {
 	return Position{pos[0] + p[0], pos[1] + p[1]}
@@ -42,6 +67,13 @@ func (pos Position) Hash() []byte {
 	}
 }
 
+// posFromHash decodes a Position from a hash previously returned by Position.Hash.
+func posFromHash(b []byte) Position {
+	a := int32(b[0]) | int32(b[1])<<8 | int32(b[2])<<16 | int32(b[3])<<24
+	c := int32(b[4]) | int32(b[5])<<8 | int32(b[6])<<16 | int32(b[7])<<24
+	return Position{int(a), int(c)}
+}
+
 // Bounds returns the bounds of the Plot present at this position. Blocks may only be edited within these
 // block positions.
 func (pos Position) Bounds(settings Settings) (min, max cube.Pos) {
@@ -63,9 +95,82 @@ func (pos Position) Absolute(settings Settings) cube.Pos {
 	return cube.Pos{baseX, 0, baseZ}
 }
 
-// TeleportPosition returns an absolute mgl64.Vec3 that can be used for teleporting the player.
-func (pos Position) TeleportPosition(settings Settings) mgl64.Vec3 {
-	return pos.Absolute(settings).Add(cube.Pos{2, RoadHeight, 2}).Vec3Middle()
+// TeleportPosition returns an absolute mgl64.Vec3 on the road at the corner of the plot that can be used for
+// teleporting the player. The road is searched for a location where the player does not end up inside of
+// blocks.
+func (pos Position) TeleportPosition(tx *world.Tx, settings Settings) mgl64.Vec3 {
+	base := pos.Absolute(settings)
+	if safe, ok := safePosition(tx, base.Add(cube.Pos{2, 0, 2}), 2); ok {
+		return safe.Vec3Middle()
+	}
+	return base.Add(cube.Pos{2, RoadHeight, 2}).Vec3Middle()
+}
+
+// Middle returns an absolute mgl64.Vec3 in the middle of the plot that can be used for teleporting the player.
+// Like with TeleportPosition, a location is searched where the player does not end up inside of blocks.
+func (pos Position) Middle(tx *world.Tx, settings Settings) mgl64.Vec3 {
+	min, _ := pos.Bounds(settings)
+	middle := min.Add(cube.Pos{settings.PlotWidth / 2, 0, settings.PlotWidth / 2})
+	if safe, ok := safePosition(tx, middle, settings.PlotWidth/2-1); ok {
+		return safe.Vec3Middle()
+	}
+	return middle.Add(cube.Pos{0, RoadHeight, 0}).Vec3Middle()
+}
+
+// safePosition searches for a location that a player can safely be teleported to, starting at the column of
+// the cube.Pos passed and moving outwards up to the radius passed. A location is safe if the highest block
+// in the column is not a liquid and if there is enough space above it for a player. False is returned if no
+// such location could be found.
+func safePosition(tx *world.Tx, start cube.Pos, radius int) (cube.Pos, bool) {
+	for r := 0; r <= radius; r++ {
+		for x := -r; x <= r; x++ {
+			for z := -r; z <= r; z++ {
+				if x != -r && x != r && z != -r && z != r {
+					// Only check the outer ring, the inner positions were already checked.
+					continue
+				}
+				column := start.Add(cube.Pos{x, 0, z})
+				y := tx.HighestBlock(column[0], column[2])
+				top := cube.Pos{column[0], y, column[2]}
+				if _, liquid := tx.Liquid(top); liquid || y+2 > tx.Range().Max() {
+					continue
+				}
+				if _, air := tx.Block(top).(block.Air); air {
+					// The column is empty, so there is nothing to stand on.
+					continue
+				}
+				return top.Side(cube.FaceUp), true
+			}
+		}
+	}
+	return cube.Pos{}, false
+}
+
+// SafeHome checks if players may safely be teleported to the mgl64.Vec3 passed, which is the case if they
+// stand on a block there without ending up inside of blocks or liquids.
+func SafeHome(tx *world.Tx, home mgl64.Vec3) bool {
+	body := cube.Box(-0.3, 0, -0.3, 0.3, 1.8, 0.3).Translate(home)
+	feet := cube.Box(-0.3, -0.1, -0.3, 0.3, 0, 0.3).Translate(home)
+	low, high := cube.PosFromVec3(feet.Min()), cube.PosFromVec3(body.Max())
+	supported := false
+	for x := low[0]; x <= high[0]; x++ {
+		for y := low[1]; y <= high[1]; y++ {
+			for z := low[2]; z <= high[2]; z++ {
+				pos := cube.Pos{x, y, z}
+				if _, liquid := tx.Liquid(pos); liquid {
+					return false
+				}
+				for _, box := range tx.Block(pos).Model().BBox(pos, tx) {
+					box = box.Translate(pos.Vec3())
+					if box.IntersectsWith(body) {
+						return false
+					}
+					supported = supported || box.IntersectsWith(feet)
+				}
+			}
+		}
+	}
+	return supported
 }
 
 // Within checks if a cube.Pos is within the minimum and maximum cube.Pos passed.
@@ -75,6 +180,24 @@ func Within(pos, min, max cube.Pos) bool {
 		(pos[2] >= min[2] && pos[2] <= max[2])
 }
 
+// borderOpts are the world.SetOpts used to set the border of a plot.
+var borderOpts = &world.SetOpts{
+	DisableBlockUpdates:       true,
+	DisableLiquidDisplacement: true,
+}
+
+// SetBorder sets the border of the plot at the Position to the world.Block passed.
+func (pos Position) SetBorder(tx *world.Tx, settings Settings, b world.Block) {
+	min, _ := pos.Bounds(settings)
+	for x := -1; x < settings.PlotWidth+1; x++ {
+		for z := -1; z < settings.PlotWidth+1; z++ {
+			if x == -1 || x == settings.PlotWidth || z == -1 || z == settings.PlotWidth {
+				tx.SetBlock(min.Add(cube.Pos{x, 22, z}), b, borderOpts)
+			}
+		}
+	}
+}
+
 // Reset resets the Plot at the Position in the world.World passed. The Settings are used to determine the
 // bounds of the plot.
 func (pos Position) Reset(tx *world.Tx, settings Settings) {
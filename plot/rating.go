@@ -0,0 +1,239 @@
+package plot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"github.com/google/uuid"
+	"strconv"
+)
+
+const (
+	// votePrefix is the prefix of keys that hold the Vote of a player on a plot. The key is followed by the
+	// hash of the Position of the plot and the UUID of the player.
+	votePrefix = "plots:vote:"
+	// topPrefix is the prefix of keys that index plots by their score on a Leaderboard. The key is followed by
+	// the name of the leaderboard, the score of the plot as a big endian uint64 and the hash of its Position.
+	topPrefix = "plots:top:"
+)
+
+// Vote is the vote of a single player on a Plot. Each player may like a plot once and may give it a rating
+// from 1 to 5, which may be changed later.
+type Vote struct {
+	Liked  bool `json:",omitempty"`
+	Rating int  `json:",omitempty"`
+}
+
+// ErrAlreadyLiked is returned by DB.Like if the player already liked the plot.
+var ErrAlreadyLiked = errors.New("plot already liked")
+
+// Leaderboard is a ranking of plots by a score, such as the amount of likes of the plot. Plots are indexed
+// by their score in the DB, so that the top plots may be read without reading every plot.
+type Leaderboard string
+
+const (
+	LeaderboardLikes  Leaderboard = "likes"
+	LeaderboardRating Leaderboard = "rating"
+	LeaderboardVisits Leaderboard = "visits"
+)
+
+// Leaderboards returns all Leaderboards that plots are ranked on.
+func Leaderboards() []Leaderboard {
+	return []Leaderboard{LeaderboardLikes, LeaderboardRating, LeaderboardVisits}
+}
+
+// score returns the score of the Plot passed on the Leaderboard. Plots with a score of 0 are not ranked.
+func (l Leaderboard) score(p *Plot) uint64 {
+	switch l {
+	case LeaderboardLikes:
+		return uint64(p.Likes)
+	case LeaderboardRating:
+		// The average rating is multiplied so that plots with an average of, for example, 4.5 rank above
+		// plots with an average of 4.4. The amount of ratings is used to break ties.
+		if p.Ratings == 0 {
+			return 0
+		}
+		return uint64(p.RatingSum*1000/p.Ratings)<<32 | uint64(p.Ratings)
+	case LeaderboardVisits:
+		return uint64(p.Visits)
+	}
+	return 0
+}
+
+// key returns the key under which the plot at the Position passed is indexed with the score passed.
+func (l Leaderboard) key(pos Position, score uint64) []byte {
+	key := append([]byte(topPrefix+string(l)+":"), make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(key)-8:], score)
+	return append(key, pos.Hash()...)
+}
+
+// Rating returns the average rating of the Plot. 0 is returned if the plot was never rated.
+func (p *Plot) Rating() float64 {
+	if p.Ratings == 0 {
+		return 0
+	}
+	return float64(p.RatingSum) / float64(p.Ratings)
+}
+
+// voteKey returns the key under which the Vote of the player with the UUID passed on the plot at the Position
+// passed is stored.
+func voteKey(pos Position, id uuid.UUID) []byte {
+	return append(append([]byte(votePrefix), pos.Hash()...), id[:]...)
+}
+
+// Vote reads the Vote of the player with the UUID passed on the plot at the Position passed. If the player
+// has not voted on the plot, an empty Vote is returned.
+func (db *DB) Vote(pos Position, id uuid.UUID) (Vote, error) {
+	var v Vote
+	val, err := db.ldb.Get(voteKey(pos, id), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return v, nil
+	} else if err != nil {
+		return v, fmt.Errorf("vote: %w", err)
+	}
+	if err := json.Unmarshal(val, &v); err != nil {
+		return v, fmt.Errorf("vote: %w", err)
+	}
+	return v, nil
+}
+
+// Like adds a like of the player with the UUID passed to the Plot at the Position passed. ErrAlreadyLiked is
+// returned if the player already liked the plot.
+func (db *DB) Like(pos Position, p *Plot, id uuid.UUID) error {
+	v, err := db.Vote(pos, id)
+	if err != nil {
+		return fmt.Errorf("like: %w", err)
+	}
+	if v.Liked {
+		return fmt.Errorf("like: %w", ErrAlreadyLiked)
+	}
+	v.Liked = true
+	if err := db.storeVote(pos, p, id, v, func() { p.Likes++ }); err != nil {
+		return fmt.Errorf("like: %w", err)
+	}
+	return nil
+}
+
+// Rate sets the rating of the player with the UUID passed on the Plot at the Position passed. If the player
+// rated the plot before, its previous rating is replaced.
+func (db *DB) Rate(pos Position, p *Plot, id uuid.UUID, rating int) error {
+	if rating < 1 || rating > 5 {
+		return fmt.Errorf("rate: rating must be between 1 and 5, got %v", rating)
+	}
+	v, err := db.Vote(pos, id)
+	if err != nil {
+		return fmt.Errorf("rate: %w", err)
+	}
+	previous := v.Rating
+	v.Rating = rating
+	err = db.storeVote(pos, p, id, v, func() {
+		if previous == 0 {
+			p.Ratings++
+		}
+		p.RatingSum += rating - previous
+	})
+	if err != nil {
+		return fmt.Errorf("rate: %w", err)
+	}
+	return nil
+}
+
+// storeVote applies the change passed to the Plot and stores it together with the Vote passed and the new
+// scores of the plot on all Leaderboards.
+func (db *DB) storeVote(pos Position, p *Plot, id uuid.UUID, v Vote, change func()) error {
+	batch := new(leveldb.Batch)
+	vb, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	batch.Put(voteKey(pos, id), vb)
+	return db.storeScores(batch, pos, p, change)
+}
+
+// RecordVisit adds a visit to the Plot at the Position passed and stores it.
+func (db *DB) RecordVisit(pos Position, p *Plot) error {
+	if err := db.storeScores(new(leveldb.Batch), pos, p, func() { p.Visits++ }); err != nil {
+		return fmt.Errorf("record visit: %w", err)
+	}
+	return nil
+}
+
+// storeScores applies the change passed to the Plot, after which the plot is stored in the batch passed along
+// with any changes to its position on the Leaderboards. The batch is then written to the DB.
+func (db *DB) storeScores(batch *leveldb.Batch, pos Position, p *Plot, change func()) error {
+	previous := make(map[Leaderboard]uint64, len(Leaderboards()))
+	for _, l := range Leaderboards() {
+		previous[l] = l.score(p)
+	}
+	change()
+	for _, l := range Leaderboards() {
+		if score := l.score(p); score != previous[l] {
+			if previous[l] != 0 {
+				batch.Delete(l.key(pos, previous[l]))
+			}
+			if score != 0 {
+				batch.Put(l.key(pos, score), nil)
+			}
+		}
+	}
+	return db.storePlotBatch(batch, pos, p)
+}
+
+// removeVotes removes all votes on the plot at the Position passed from the DB by adding the deletions to the
+// batch passed.
+func (db *DB) removeVotes(batch *leveldb.Batch, pos Position) {
+	it := db.ldb.NewIterator(util.BytesPrefix(append([]byte(votePrefix), pos.Hash()...)), nil)
+	defer it.Release()
+	for it.Next() {
+		batch.Delete(append([]byte(nil), it.Key()...))
+	}
+}
+
+// Top returns the Positions of the plots ranked highest on the Leaderboard passed, skipping the first offset
+// plots and returning at most n plots. True is returned if more plots are ranked after those returned.
+func (db *DB) Top(l Leaderboard, offset, n int) ([]Position, bool, error) {
+	it := db.ldb.NewIterator(util.BytesPrefix([]byte(topPrefix+string(l)+":")), nil)
+	defer it.Release()
+
+	positions := make([]Position, 0, n)
+	for ok := it.Last(); ok; ok = it.Prev() {
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if len(positions) == n {
+			return positions, true, nil
+		}
+		key := it.Key()
+		positions = append(positions, posFromHash(key[len(key)-8:]))
+	}
+	if err := it.Error(); err != nil {
+		return nil, false, fmt.Errorf("top: %w", err)
+	}
+	return positions, false, nil
+}
+
+func init() {
+	for name, value := range map[string]func(p *Plot) int{
+		"likes":  func(p *Plot) int { return p.Likes },
+		"rating": func(p *Plot) int { return int(p.Rating()) },
+		"visits": func(p *Plot) int { return p.Visits },
+	} {
+		RegisterFilter(name, func(op, s string) (Filter, error) {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("%v must be a number", name)
+			}
+			if op == ":" {
+				// 'likes:5' is more likely to mean 'at least 5 likes' than 'exactly 5 likes'.
+				op = ">="
+			}
+			return FilterFunc(func(_ Position, p *Plot) bool {
+				return compare(op, value(p), n)
+			}), nil
+		})
+	}
+}
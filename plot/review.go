@@ -0,0 +1,153 @@
+package plot
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"time"
+)
+
+// reviewPrefix is the prefix of keys that make up the review queue. The key is followed by the completion time
+// of the plot as a big endian uint64 and the hash of its Position, so that plots are reviewed in the order in
+// which they were completed.
+const reviewPrefix = "plots:review:"
+
+// ReviewStatus is the status of a completed Plot in the review workflow.
+type ReviewStatus uint8
+
+const (
+	// ReviewNone is the ReviewStatus of plots that were never completed.
+	ReviewNone ReviewStatus = iota
+	// ReviewPending is the ReviewStatus of plots that were completed and are waiting in the review queue.
+	ReviewPending
+	// ReviewApproved is the ReviewStatus of plots that were approved by staff.
+	ReviewApproved
+	// ReviewRejected is the ReviewStatus of plots that were rejected by staff. Rejected plots are no longer
+	// completed, so that the builder may improve them and complete them again.
+	ReviewRejected
+)
+
+// String ...
+func (s ReviewStatus) String() string {
+	switch s {
+	case ReviewNone:
+		return "not reviewed"
+	case ReviewPending:
+		return "pending review"
+	case ReviewApproved:
+		return "approved"
+	case ReviewRejected:
+		return "rejected"
+	}
+	panic("should never happen")
+}
+
+// Done checks if the Plot was completed using DB.Complete. Completed plots cannot be edited by anyone.
+func (p *Plot) Done() bool {
+	return !p.Completed.IsZero()
+}
+
+// reviewKey returns the key of the plot at the Position passed in the review queue.
+func reviewKey(pos Position, p *Plot) []byte {
+	key := append([]byte(reviewPrefix), make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(key)-8:], uint64(p.Completed.UnixNano()))
+	return append(key, pos.Hash()...)
+}
+
+// ErrNotPending is returned by DB.Approve and DB.Reject if the plot passed is not pending review.
+var ErrNotPending = errors.New("plot is not pending review")
+
+// Complete marks the Plot at the Position passed as done and adds it to the end of the review queue.
+func (db *DB) Complete(pos Position, p *Plot) error {
+	p = p.Clone()
+	p.Completed, p.Review, p.ReviewNote = time.Now(), ReviewPending, ""
+	batch := new(leveldb.Batch)
+	batch.Put(reviewKey(pos, p), nil)
+	if err := db.storePlotBatch(batch, pos, p); err != nil {
+		return fmt.Errorf("complete: %w", err)
+	}
+	return nil
+}
+
+// Locked checks if the blocks of the plot at the Position passed may not be changed by anyone, because the
+// plot is done or because it was submitted to a contest that is open for submissions. Commands that change the
+// blocks of a plot must check this.
+func (db *DB) Locked(pos Position) bool {
+	if db.ContestLocked(pos) {
+		return true
+	}
+	p, err := db.Plot(pos)
+	return err == nil && p.Done()
+}
+
+// NextReview returns the Position of the plot that has been waiting in the review queue for the longest
+// time. False is returned if the queue is empty.
+func (db *DB) NextReview() (Position, bool, error) {
+	it := db.ldb.NewIterator(util.BytesPrefix([]byte(reviewPrefix)), nil)
+	defer it.Release()
+	if !it.First() {
+		return Position{}, false, it.Error()
+	}
+	key := it.Key()
+	return posFromHash(key[len(key)-8:]), true, nil
+}
+
+// ReviewQueueLength returns the amount of plots waiting in the review queue.
+func (db *DB) ReviewQueueLength() int {
+	it := db.ldb.NewIterator(util.BytesPrefix([]byte(reviewPrefix)), nil)
+	defer it.Release()
+	n := 0
+	for it.Next() {
+		n++
+	}
+	return n
+}
+
+// Approve approves the Plot at the Position passed, which must be pending review, and removes it from the
+// review queue. The plot remains completed.
+func (db *DB) Approve(pos Position, p *Plot, note string) error {
+	if p.Review != ReviewPending {
+		return fmt.Errorf("approve: %w", ErrNotPending)
+	}
+	batch := new(leveldb.Batch)
+	batch.Delete(reviewKey(pos, p))
+	p = p.Clone()
+	p.Review, p.ReviewNote = ReviewApproved, note
+	if err := db.storePlotBatch(batch, pos, p); err != nil {
+		return fmt.Errorf("approve: %w", err)
+	}
+	return nil
+}
+
+// Reject rejects the Plot at the Position passed, which must be pending review, for the reason passed. The
+// plot is removed from the review queue and is no longer completed, so that it may be edited again.
+func (db *DB) Reject(pos Position, p *Plot, reason string) error {
+	if p.Review != ReviewPending {
+		return fmt.Errorf("reject: %w", ErrNotPending)
+	}
+	batch := new(leveldb.Batch)
+	batch.Delete(reviewKey(pos, p))
+	p = p.Clone()
+	p.Completed, p.Review, p.ReviewNote = time.Time{}, ReviewRejected, reason
+	if err := db.storePlotBatch(batch, pos, p); err != nil {
+		return fmt.Errorf("reject: %w", err)
+	}
+	return nil
+}
+
+// storePlotBatch adds the Plot passed to the batch passed, after which the batch is written to the DB.
+func (db *DB) storePlotBatch(batch *leveldb.Batch, pos Position, p *Plot) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	batch.Put(pos.Hash(), b)
+	if err := db.ldb.Write(batch, nil); err != nil {
+		return err
+	}
+	db.cache[pos] = p
+	return nil
+}
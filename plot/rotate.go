@@ -0,0 +1,180 @@
+package plot
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"maps"
+	"strings"
+)
+
+// Rotate returns a copy of the Build rotated clockwise around the Y axis by the amount of quarter turns
+// passed. Blocks facing a direction, such as chests, stairs and logs, are rotated along with the Build. An
+// error is returned if the Build holds a block with an orientation that cannot be rotated.
+func (b *Build) Rotate(turns int) (*Build, error) {
+	turns = ((turns % 4) + 4) % 4
+	if turns == 0 {
+		return b, nil
+	}
+	r := newBuild(b.length, b.height, b.width)
+	r.y = b.y
+	for x := 0; x < b.width; x++ {
+		for z := 0; z < b.length; z++ {
+			for y := 0; y < b.height; y++ {
+				from, to := b.index(x, y, z), r.index(b.length-1-z, y, x)
+				bl, liq := b.At(x, y, z, nil)
+				bl, err := rotateBlock(bl)
+				if err != nil {
+					return nil, fmt.Errorf("rotate: %w", err)
+				}
+				if _, ok := bl.(world.NBTer); ok {
+					r.entities[to] = bl
+				}
+				r.set(to, bl)
+				if _, ok := b.liquids[from]; ok {
+					r.liquids[to] = liq
+				}
+			}
+		}
+	}
+	return r.Rotate(turns - 1)
+}
+
+// cardinalDirections holds the next direction clockwise of every cardinal direction.
+var cardinalDirections = map[string]string{"north": "east", "east": "south", "south": "west", "west": "north"}
+
+// rotateCardinal rotates a property holding the name of a direction, such as 'north' or 'up'.
+func rotateCardinal(v any) any {
+	if dir, ok := cardinalDirections[v.(string)]; ok {
+		return dir
+	}
+	return v
+}
+
+// rotateAxis rotates a property holding the name of an axis.
+func rotateAxis(v any) any {
+	switch v.(string) {
+	case "x":
+		return "z"
+	case "z":
+		return "x"
+	}
+	return v
+}
+
+// rotateWords rotates a property holding directions separated by underscores, such as 'down_east'.
+func rotateWords(v any) any {
+	switch s := v.(string); {
+	case strings.HasSuffix(s, "_east_west"):
+		return strings.TrimSuffix(s, "_east_west") + "_north_south"
+	case strings.HasSuffix(s, "_north_south"):
+		return strings.TrimSuffix(s, "_north_south") + "_east_west"
+	}
+	words := strings.Split(v.(string), "_")
+	for i, w := range words {
+		words[i] = rotateCardinal(w).(string)
+	}
+	return strings.Join(words, "_")
+}
+
+// rotateIndex returns a function that rotates a property holding a number using the map passed. Numbers not
+// present in the map are returned unchanged.
+func rotateIndex(m map[int32]int32) func(v any) any {
+	return func(v any) any {
+		if n, ok := m[v.(int32)]; ok {
+			return n
+		}
+		return v
+	}
+}
+
+// rotateBits rotates a property holding one bit for every cardinal direction, in the order south, west,
+// north and east, starting at the bit passed.
+func rotateBits(shift int) func(v any) any {
+	return func(v any) any {
+		n := v.(int32)
+		dirs := (n >> shift) & 0xf
+		dirs = ((dirs << 1) | (dirs >> 3)) & 0xf
+		return n&^(0xf<<shift) | dirs<<shift
+	}
+}
+
+// blockRotations holds functions that rotate the value of block properties holding an orientation by a
+// quarter turn clockwise, indexed by the name of the property.
+var blockRotations = map[string]func(v any) any{
+	"minecraft:cardinal_direction": rotateCardinal,
+	"minecraft:facing_direction":   rotateCardinal,
+	"minecraft:block_face":         rotateCardinal,
+	"torch_facing_direction":       rotateCardinal,
+	"pillar_axis":                  rotateAxis,
+	"portal_axis":                  rotateAxis,
+	"lever_direction":              rotateWords,
+	"orientation":                  rotateWords,
+	// East, west, south, north.
+	"weirdo_direction": rotateIndex(map[int32]int32{0: 2, 2: 1, 1: 3, 3: 0}),
+	// Down, up, north, south, west, east.
+	"facing_direction": rotateIndex(map[int32]int32{2: 5, 5: 3, 3: 4, 4: 2}),
+	// South, west, north, east.
+	"direction": rotateIndex(map[int32]int32{0: 1, 1: 2, 2: 3, 3: 0}),
+	// West, east, north, south.
+	"coral_direction": rotateIndex(map[int32]int32{0: 2, 2: 1, 1: 3, 3: 0}),
+	// Straight, ascending east, west, north and south, and curved south-east, south-west, north-west and
+	// north-east.
+	"rail_direction":            rotateIndex(map[int32]int32{0: 1, 1: 0, 2: 5, 5: 3, 3: 4, 4: 2, 6: 7, 7: 8, 8: 9, 9: 6}),
+	"ground_sign_direction":     func(v any) any { return (v.(int32) + 4) % 16 },
+	"vine_direction_bits":       rotateBits(0),
+	"multi_face_direction_bits": rotateBits(2),
+}
+
+// unrotatable holds the names of block properties holding an orientation that cannot be rotated.
+var unrotatable = []string{"rotation", "coral_fan_direction"}
+
+// wallConnections holds the properties of walls that hold their connection in every direction, indexed by the
+// direction.
+var wallConnections = map[string]string{
+	"north": "wall_connection_type_north",
+	"east":  "wall_connection_type_east",
+	"south": "wall_connection_type_south",
+	"west":  "wall_connection_type_west",
+}
+
+// rotateBlock rotates a world.Block by a quarter turn clockwise. Blocks without an orientation are returned
+// unchanged. An error is returned if the block has an orientation that cannot be rotated.
+func rotateBlock(b world.Block) (world.Block, error) {
+	if b == nil {
+		return nil, nil
+	}
+	name, properties := b.EncodeBlock()
+	rotatedProperties := maps.Clone(properties)
+	changed := false
+	for k, v := range properties {
+		if f, ok := blockRotations[k]; ok {
+			// Orientations along the Y axis, such as 'up', are not changed by the rotation.
+			if rotated := f(v); rotated != v {
+				rotatedProperties[k], changed = rotated, true
+			}
+		}
+	}
+	for _, k := range unrotatable {
+		if _, ok := properties[k]; ok {
+			return nil, fmt.Errorf("%v cannot be rotated", name)
+		}
+	}
+	for dir, k := range wallConnections {
+		if v, ok := properties[k]; ok {
+			rotatedProperties[wallConnections[cardinalDirections[dir]]], changed = v, true
+		}
+	}
+	if !changed {
+		return b, nil
+	}
+	rotated, ok := world.BlockByName(name, rotatedProperties)
+	if !ok {
+		return b, nil
+	}
+	if nbter, ok := b.(world.NBTer); ok {
+		if r, ok := rotated.(world.NBTer); ok {
+			rotated = r.DecodeNBT(nbter.EncodeNBT()).(world.Block)
+		}
+	}
+	return rotated, nil
+}
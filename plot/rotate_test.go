@@ -0,0 +1,111 @@
+package plot
+
+import (
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"testing"
+)
+
+func TestRotateBlock(t *testing.T) {
+	oak := block.Planks{Wood: block.OakWood()}
+	for _, tc := range []struct {
+		name     string
+		in, want world.Block
+	}{
+		{"stone", block.Stone{}, block.Stone{}},
+		{"stairs", block.Stairs{Block: oak, Facing: cube.North}, block.Stairs{Block: oak, Facing: cube.East}},
+		{"upside down stairs", block.Stairs{Block: oak, Facing: cube.West, UpsideDown: true}, block.Stairs{Block: oak, Facing: cube.North, UpsideDown: true}},
+		{"log x", block.Log{Wood: block.OakWood(), Axis: cube.X}, block.Log{Wood: block.OakWood(), Axis: cube.Z}},
+		{"log y", block.Log{Wood: block.OakWood(), Axis: cube.Y}, block.Log{Wood: block.OakWood(), Axis: cube.Y}},
+		{"torch", block.Torch{Type: block.NormalFire(), Facing: cube.FaceSouth}, block.Torch{Type: block.NormalFire(), Facing: cube.FaceWest}},
+		{"standing torch", block.Torch{Type: block.NormalFire(), Facing: cube.FaceDown}, block.Torch{Type: block.NormalFire(), Facing: cube.FaceDown}},
+		{"ladder", block.Ladder{Facing: cube.FaceNorth}, block.Ladder{Facing: cube.FaceEast}},
+		{"wall sign", block.Sign{Wood: block.OakWood(), Attach: block.WallAttachment(cube.East)}, block.Sign{Wood: block.OakWood(), Attach: block.WallAttachment(cube.South)}},
+		{"standing sign", block.Sign{Wood: block.OakWood(), Attach: block.StandingAttachment(14)}, block.Sign{Wood: block.OakWood(), Attach: block.StandingAttachment(2)}},
+		{"standing skull", block.Skull{Attach: block.StandingAttachment(0)}, block.Skull{Attach: block.StandingAttachment(0)}},
+		{"wall", block.Wall{Block: block.Cobblestone{}, NorthConnection: block.ShortWallConnection(), Post: true}, block.Wall{Block: block.Cobblestone{}, EastConnection: block.ShortWallConnection(), Post: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := rotateBlock(tc.in)
+			if err != nil {
+				t.Fatalf("rotateBlock: %v", err)
+			}
+			if world.BlockRuntimeID(got) != world.BlockRuntimeID(tc.want) {
+				t.Errorf("rotateBlock(%v) = %v, want %v", encodeBlock(tc.in), encodeBlock(got), encodeBlock(tc.want))
+			}
+		})
+	}
+}
+
+func TestRotateBlockKeepsBlockEntity(t *testing.T) {
+	c := block.NewChest()
+	c.Facing, c.CustomName = cube.North, "Loot"
+	got, err := rotateBlock(c)
+	if err != nil {
+		t.Fatalf("rotateBlock: %v", err)
+	}
+	rotated, ok := got.(block.Chest)
+	if !ok {
+		t.Fatalf("rotateBlock(chest) = %T, want block.Chest", got)
+	}
+	if rotated.Facing != cube.East || rotated.CustomName != "Loot" {
+		t.Errorf("rotateBlock(chest) = facing %v, name %q, want facing east, name \"Loot\"", rotated.Facing, rotated.CustomName)
+	}
+}
+
+func TestRotateBlockUnrotatable(t *testing.T) {
+	fan, ok := world.BlockByName("minecraft:tube_coral_fan", map[string]any{"coral_fan_direction": int32(0)})
+	if !ok {
+		t.Fatal("tube_coral_fan is not registered")
+	}
+	if _, err := rotateBlock(fan); err == nil {
+		t.Error("rotateBlock(tube_coral_fan) returned no error")
+	}
+}
+
+// TestRotateBlockFullTurn checks that rotating any registered block four times results in the same block.
+// Blocks with block entities are skipped, as decoding their data may reset state, such as that of open
+// barrels.
+func TestRotateBlockFullTurn(t *testing.T) {
+	for rid := uint32(0); ; rid++ {
+		b, ok := world.BlockByRuntimeID(rid)
+		if !ok {
+			break
+		}
+		if _, ok := b.(world.NBTer); ok {
+			continue
+		}
+		rotated := b
+		var err error
+		for i := 0; i < 4 && err == nil; i++ {
+			rotated, err = rotateBlock(rotated)
+		}
+		if err == nil && world.BlockRuntimeID(rotated) != rid {
+			t.Errorf("rotating %v four times resulted in %v", encodeBlock(b), encodeBlock(rotated))
+		}
+	}
+}
+
+func TestBuildRotate(t *testing.T) {
+	b := newBuild(2, 1, 3)
+	b.set(b.index(0, 0, 0), block.Stone{})
+	b.set(b.index(1, 0, 2), block.Log{Wood: block.OakWood(), Axis: cube.X})
+	r, err := b.Rotate(1)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if w, h, l := r.Size(); w != 3 || h != 1 || l != 2 {
+		t.Fatalf("rotated size = %vx%vx%v, want 3x1x2", w, h, l)
+	}
+	// A quarter turn clockwise moves the block at (x, z) to (length-1-z, x).
+	if got, _ := r.At(2, 0, 0, nil); got != (block.Stone{}) {
+		t.Errorf("rotated block at 2 0 0 = %v, want stone", encodeBlock(got))
+	}
+	if got, _ := r.At(0, 0, 1, nil); got != (block.Log{Wood: block.OakWood(), Axis: cube.Z}) {
+		t.Errorf("rotated block at 0 0 1 = %v, want log along z", encodeBlock(got))
+	}
+	if full, err := b.Rotate(4); err != nil || full != b {
+		t.Errorf("Rotate(4) = %v, %v, want the build itself", full, err)
+	}
+}
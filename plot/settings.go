@@ -1,6 +1,11 @@
 package plot
 
-import "github.com/df-mc/dragonfly/server/world"
+import (
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
 
 // Settings holds the settings for a plot Generator. These settings may be changed in order to change the
 // appearance of the plots generated.
@@ -18,4 +23,57 @@ type Settings struct {
 	// MaximumPlots is the maximum amount of plots that a player is allowed to claim. Trying to claim more
 	// than this will result in an error.
 	MaximumPlots int
+	// ApprovalBonus is the amount of plots that a player may claim in addition to MaximumPlots for every plot
+	// of the player that was approved by staff.
+	ApprovalBonus int
+	// Border is the maximum distance in plots from the plot at 0;0 at which plots may be claimed. Plots with
+	// an X or Z further than this cannot be claimed. If 0, there is no border.
+	Border int
+	// Reserved is a list of plots that cannot be claimed by players, such as the plots around the spawn.
+	Reserved []Position
+	// ExpiryPeriod is the time after which the plots of an owner that has not joined or edited any plot are
+	// marked as expiring. If 0, plots never expire.
+	ExpiryPeriod time.Duration
+	// ExpiryGrace is the time after a plot was marked as expiring after which it is archived and reset. The
+	// owner may restore archived plots when it returns.
+	ExpiryGrace time.Duration
+	// TrashRetention is the time that deleted plots are kept in the trash, during which their owner may
+	// restore them using /plot restore. If 0, deleted plots are kept until they are restored.
+	TrashRetention time.Duration
+	// UndoWindow is the time after clearing or deleting a plot during which the player may undo it using
+	// /plot undo. If 0, these actions cannot be undone.
+	UndoWindow time.Duration
+	// MaximumSnapshots is the maximum amount of snapshots that a player may save across all of its plots
+	// using /plot snapshot save. Automatic snapshots do not count towards this limit.
+	MaximumSnapshots int
+	// AutoSnapshots is the amount of daily snapshots kept of every plot that is being edited. If 0, no
+	// snapshots are created automatically.
+	AutoSnapshots int
+	// BlockLogRetention is the time that changes of blocks in plots are kept in the block log, during which
+	// they may be inspected and rolled back. If 0, changes are kept forever.
+	BlockLogRetention time.Duration
+	// MaximumLibrary is the maximum amount of structures that a player may save in its personal library using
+	// /plot lib save.
+	MaximumLibrary int
+	// MaximumLibrarySize is the maximum total size in bytes of the files of the structures in the library of a
+	// player. Because a single structure may be as large as a full plot, this limits the storage used by a
+	// library, while MaximumLibrary only limits the amount of structures. If 0, the size is not limited.
+	MaximumLibrarySize int64
+	// Admins is a list of UUIDs of players that may run administrative commands, such as creating contests.
+	// Admins are identified by UUID rather than by name, because names of players may be taken by others.
+	Admins []uuid.UUID
+}
+
+// Admin checks if the player with the UUID passed is one of the Admins in the Settings.
+func (s Settings) Admin(id uuid.UUID) bool {
+	return slices.Contains(s.Admins, id)
+}
+
+// Claimable checks if the plot at the Position passed may be claimed by players according to the Settings.
+// It does not check if the plot is already claimed.
+func (s Settings) Claimable(pos Position) bool {
+	if s.Border > 0 && (abs(pos[0]) > s.Border || abs(pos[1]) > s.Border) {
+		return false
+	}
+	return !slices.Contains(s.Reserved, pos)
 }
@@ -0,0 +1,250 @@
+package plot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// snapshotPrefix is the prefix of keys that hold a Snapshot. The key is followed by the hash of the Position
+// of the plot and the name of the snapshot.
+const snapshotPrefix = "plots:snapshot:"
+
+// autoSnapshotPrefix is the prefix of the names of snapshots created automatically by DB.AutoSnapshot.
+// Snapshots created by players cannot have names with this prefix.
+const autoSnapshotPrefix = "auto-"
+
+// nameRegex is a regular expression that names of snapshots, templates and library structures must match.
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{0,23}$`)
+
+// ValidSnapshotName checks if the name passed may be used as the name of a snapshot saved by a player.
+func ValidSnapshotName(name string) bool {
+	return nameRegex.MatchString(name) && !strings.HasPrefix(name, autoSnapshotPrefix)
+}
+
+// Snapshot is a named copy of the blocks in a plot at a point in time. The plot may be rolled back to the
+// Snapshot using DB.RestoreSnapshot. Snapshots are removed when the plot is removed or transferred to another
+// player, but are kept in the Archive of the plot when it is archived.
+type Snapshot struct {
+	// Pos is the Position of the plot that the Snapshot was taken of.
+	Pos Position
+	// Name is the name of the Snapshot, which is unique within the plot.
+	Name string
+	// AuthorName is the name of the player that saved the Snapshot. It is empty for snapshots created
+	// automatically.
+	AuthorName string `json:",omitempty"`
+	// Created is the time at which the Snapshot was saved.
+	Created time.Time
+	// Auto specifies if the Snapshot was created automatically by DB.AutoSnapshot. Automatic snapshots do
+	// not count towards Settings.MaximumSnapshots.
+	Auto bool `json:",omitempty"`
+}
+
+// snapshotRange returns the range of keys of all snapshots of the plot at the Position passed.
+func snapshotRange(pos Position) *util.Range {
+	return util.BytesPrefix(append([]byte(snapshotPrefix), pos.Hash()...))
+}
+
+// snapshotKey returns the key under which the Snapshot passed is stored.
+func snapshotKey(s *Snapshot) []byte {
+	return append(append([]byte(snapshotPrefix), s.Pos.Hash()...), s.Name...)
+}
+
+// snapshotDir returns the directory that holds the blocks of all snapshots of the plot at the Position passed.
+func (db *DB) snapshotDir(pos Position) string {
+	return filepath.Join(db.dir, "snapshots", fmt.Sprintf("%v_%v", pos[0], pos[1]))
+}
+
+// snapshotPath returns the path of the file that holds the blocks of the Snapshot passed.
+func (db *DB) snapshotPath(s *Snapshot) string {
+	return filepath.Join(db.snapshotDir(s.Pos), s.Name+".build")
+}
+
+// SaveSnapshot captures the blocks of the plot at the Position passed and saves them as a Snapshot with the
+// name passed. An existing snapshot of the plot with the same name is overwritten.
+func (db *DB) SaveSnapshot(tx *world.Tx, pos Position, name, authorName string) (*Snapshot, error) {
+	s := &Snapshot{Pos: pos, Name: name, AuthorName: authorName, Created: time.Now(), Auto: authorName == ""}
+	if err := SaveBuild(db.snapshotPath(s), CaptureBuild(tx, pos, db.settings)); err != nil {
+		return nil, fmt.Errorf("save snapshot: %w", err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("save snapshot: %w", err)
+	}
+	if err := db.ldb.Put(snapshotKey(s), b, nil); err != nil {
+		return nil, fmt.Errorf("save snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// Snapshots returns all snapshots of the plot at the Position passed, ordered from oldest to newest.
+func (db *DB) Snapshots(pos Position) ([]*Snapshot, error) {
+	it := db.ldb.NewIterator(snapshotRange(pos), nil)
+	defer it.Release()
+
+	var snapshots []*Snapshot
+	for it.Next() {
+		var s Snapshot
+		if err := json.Unmarshal(it.Value(), &s); err != nil {
+			return nil, fmt.Errorf("snapshots: %w", err)
+		}
+		snapshots = append(snapshots, &s)
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("snapshots: %w", err)
+	}
+	slices.SortFunc(snapshots, func(a, b *Snapshot) int {
+		return a.Created.Compare(b.Created)
+	})
+	return snapshots, nil
+}
+
+// Snapshot looks up the snapshot of the plot at the Position passed by its name. leveldb.ErrNotFound is
+// returned if the plot has no snapshot with the name.
+func (db *DB) Snapshot(pos Position, name string) (*Snapshot, error) {
+	b, err := db.ldb.Get(snapshotKey(&Snapshot{Pos: pos, Name: name}), nil)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+	return &s, nil
+}
+
+// SnapshotCount returns the amount of snapshots saved by players in the plots at the Positions passed, which
+// are typically all plots of a single player.
+func (db *DB) SnapshotCount(positions []Position) (int, error) {
+	n := 0
+	for _, pos := range positions {
+		snapshots, err := db.Snapshots(pos)
+		if err != nil {
+			return 0, err
+		}
+		for _, s := range snapshots {
+			if !s.Auto {
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+// RestoreSnapshot rolls the plot that the Snapshot passed was taken of back to the Snapshot, replacing all
+// blocks currently in the plot.
+func (db *DB) RestoreSnapshot(tx *world.Tx, s *Snapshot) error {
+	build, err := LoadBuild(db.snapshotPath(s))
+	if err != nil {
+		return fmt.Errorf("restore snapshot: %w", err)
+	}
+	build.Place(tx, s.Pos, db.settings)
+	return nil
+}
+
+// RemoveSnapshot permanently removes the Snapshot passed, including the blocks saved for it.
+func (db *DB) RemoveSnapshot(s *Snapshot) error {
+	if err := db.ldb.Delete(snapshotKey(s), nil); err != nil {
+		return fmt.Errorf("remove snapshot: %w", err)
+	}
+	if err := os.Remove(db.snapshotPath(s)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove snapshot: %w", err)
+	}
+	return nil
+}
+
+// removeSnapshots adds the deletion of all snapshots of the plot at the Position passed to the batch passed.
+// The files of the snapshots must be removed separately using os.RemoveAll(db.snapshotDir(pos)).
+func (db *DB) removeSnapshots(batch *leveldb.Batch, pos Position) {
+	it := db.ldb.NewIterator(snapshotRange(pos), nil)
+	defer it.Release()
+	for it.Next() {
+		batch.Delete(append([]byte(nil), it.Key()...))
+	}
+}
+
+// AutoSnapshotDue returns the positions of all plots that are due a daily automatic snapshot. These are the
+// plots that were edited since their last automatic snapshot, if that snapshot is at least a day old. The
+// snapshots should be saved using AutoSnapshot.
+func (db *DB) AutoSnapshotDue() ([]Position, error) {
+	if db.settings.AutoSnapshots <= 0 {
+		return nil, nil
+	}
+	var (
+		err    error
+		active []Position
+	)
+	for pos, p := range db.Plots(&err) {
+		if time.Since(p.LastEdit) < time.Hour*24 {
+			active = append(active, pos)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("auto snapshot due: %w", err)
+	}
+	due := make([]Position, 0, len(active))
+	for _, pos := range active {
+		p, err := db.Plot(pos)
+		if err != nil {
+			continue
+		}
+		auto, err := db.autoSnapshots(pos)
+		if err != nil {
+			return nil, fmt.Errorf("auto snapshot due: %w", err)
+		}
+		if len(auto) > 0 {
+			last := auto[len(auto)-1].Created
+			if time.Since(last) < time.Hour*24 || !p.LastEdit.After(last) {
+				continue
+			}
+		}
+		due = append(due, pos)
+	}
+	return due, nil
+}
+
+// AutoSnapshot saves an automatic snapshot of the plot at the Position passed, as returned by AutoSnapshotDue.
+// Only the latest Settings.AutoSnapshots automatic snapshots of the plot are kept. Every snapshot is saved in a
+// transaction of its own, so false is returned if the plot was removed since it was returned.
+func (db *DB) AutoSnapshot(tx *world.Tx, pos Position) (bool, error) {
+	if _, err := db.Plot(pos); errors.Is(err, leveldb.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("auto snapshot: %w", err)
+	}
+	auto, err := db.autoSnapshots(pos)
+	if err != nil {
+		return false, fmt.Errorf("auto snapshot: %w", err)
+	}
+	s, err := db.SaveSnapshot(tx, pos, autoSnapshotPrefix+time.Now().Format("2006-01-02"), "")
+	if err != nil {
+		return false, fmt.Errorf("auto snapshot: %w", err)
+	}
+	auto = append(auto, s)
+	for len(auto) > db.settings.AutoSnapshots {
+		if err := db.RemoveSnapshot(auto[0]); err != nil {
+			return true, fmt.Errorf("auto snapshot: %w", err)
+		}
+		auto = auto[1:]
+	}
+	return true, nil
+}
+
+// autoSnapshots returns the snapshots of the plot at the Position passed that were created automatically,
+// ordered from oldest to newest.
+func (db *DB) autoSnapshots(pos Position) ([]*Snapshot, error) {
+	snapshots, err := db.Snapshots(pos)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(snapshots, func(s *Snapshot) bool { return !s.Auto }), nil
+}
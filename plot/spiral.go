@@ -0,0 +1,168 @@
+package plot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/df-mc/goleveldb/leveldb"
+	"math"
+	"slices"
+)
+
+// spiralOffset returns the offset of the plot at index n in a square spiral. Index 0 is the centre of the
+// spiral. Every ring r around the centre holds 8r plots, so that ring r starts at index (2r-1)².
+func spiralOffset(n int) Position {
+	if n <= 0 {
+		return Position{}
+	}
+	r := int(math.Ceil((math.Sqrt(float64(n+1)) - 1) / 2))
+	k := n - (2*r-1)*(2*r-1)
+	switch side, j := k/(2*r), k%(2*r); side {
+	case 0:
+		return Position{r, -r + 1 + j}
+	case 1:
+		return Position{r - 1 - j, r}
+	case 2:
+		return Position{-r, r - 1 - j}
+	default:
+		return Position{-r + 1 + j, -r}
+	}
+}
+
+// spiralIndex returns the index of the offset passed in a square spiral. It is the inverse of spiralOffset.
+func spiralIndex(offset Position) int {
+	x, z := offset[0], offset[1]
+	r := max(abs(x), abs(z))
+	if r == 0 {
+		return 0
+	}
+	base := (2*r - 1) * (2*r - 1)
+	switch {
+	case x == r && z > -r:
+		return base + z + r - 1
+	case z == r:
+		return base + 2*r + r - 1 - x
+	case x == -r:
+		return base + 4*r + r - 1 - z
+	default:
+		return base + 6*r + x + r - 1
+	}
+}
+
+// SpiralIndex returns the index of the Position in a square spiral around the centre passed. The centre
+// itself has index 0, the 8 plots around it have indices 1-8, and so on.
+func (pos Position) SpiralIndex(centre Position) int {
+	return spiralIndex(Position{pos[0] - centre[0], pos[1] - centre[1]})
+}
+
+// abs returns the absolute value of the integer passed.
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// ErrNoFreePlots is returned by DB.NextFreePlot and DB.FreePlotNear if no free plot could be found.
+var ErrNoFreePlots = errors.New("no free plots")
+
+// autoCursorKey is the key under which the autoCursor is stored in the DB.
+const autoCursorKey = "plots:auto-cursor"
+
+// autoCursor is stored in the DB to remember the index in a spiral around Centre up to which all plots are
+// known to be claimed or otherwise unavailable.
+type autoCursor struct {
+	Centre Position
+	Index  int
+}
+
+// cursor reads the autoCursor from the DB. If none is stored, or if it was stored for a different centre, a
+// cursor at index 0 around the centre passed is returned.
+func (db *DB) cursor(centre Position) autoCursor {
+	c := autoCursor{Centre: centre}
+	val, err := db.ldb.Get([]byte(autoCursorKey), nil)
+	if err != nil {
+		return c
+	}
+	var stored autoCursor
+	if err := json.Unmarshal(val, &stored); err != nil || stored.Centre != centre {
+		return c
+	}
+	return stored
+}
+
+// NextFreePlot returns the Position of the free plot closest to the centre passed, searching in a spiral
+// around the centre. Plots that may not be claimed according to Settings.Claimable are skipped. A cursor is
+// stored in the DB so that plots known to be claimed do not need to be checked again with the next call.
+// ErrNoFreePlots is returned if all plots within the border are claimed.
+func (db *DB) NextFreePlot(centre Position) (Position, error) {
+	c := db.cursor(centre)
+	pos, n, err := db.searchFree(centre, c.Index, db.maxRing(centre))
+	if n != c.Index {
+		c.Index = n
+		b, _ := json.Marshal(c)
+		if err := db.ldb.Put([]byte(autoCursorKey), b, nil); err != nil {
+			return pos, fmt.Errorf("next free plot: %w", err)
+		}
+	}
+	return pos, err
+}
+
+// FreePlotNear returns the Position of the free plot closest to the centre passed, searching in a spiral
+// around the centre up to the radius passed. Unlike NextFreePlot, no cursor is used. ErrNoFreePlots is
+// returned if no free plot could be found within the radius.
+func (db *DB) FreePlotNear(centre Position, radius int) (Position, error) {
+	pos, _, err := db.searchFree(centre, 0, min(radius, db.maxRing(centre)))
+	return pos, err
+}
+
+// searchFree searches for a free plot in a spiral around the centre passed, starting at index n and stopping
+// after the ring passed. The Position found and its index are returned.
+func (db *DB) searchFree(centre Position, n, ring int) (Position, int, error) {
+	for ; n < (2*ring+1)*(2*ring+1); n++ {
+		pos := centre.Add(spiralOffset(n))
+		if !db.settings.Claimable(pos) {
+			continue
+		}
+		if _, err := db.Plot(pos); errors.Is(err, leveldb.ErrNotFound) {
+			return pos, n, nil
+		}
+	}
+	return Position{}, n, ErrNoFreePlots
+}
+
+// maxRing returns the last ring around the centre passed that holds plots within the border of the
+// Settings. If there is no border, the ring returned is limited to prevent searching forever.
+func (db *DB) maxRing(centre Position) int {
+	if db.settings.Border <= 0 {
+		return 1 << 12
+	}
+	return db.settings.Border + max(abs(centre[0]), abs(centre[1]))
+}
+
+// lowerCursor moves the cursor stored in the DB back to the plot at the Position passed if the plot comes
+// before the cursor. It is called when a plot is removed so that it may be found by NextFreePlot again.
+func (db *DB) lowerCursor(batch *leveldb.Batch, pos Position) {
+	val, err := db.ldb.Get([]byte(autoCursorKey), nil)
+	if err != nil {
+		return
+	}
+	var c autoCursor
+	if err := json.Unmarshal(val, &c); err != nil {
+		return
+	}
+	if n := pos.SpiralIndex(c.Centre); n < c.Index {
+		c.Index = n
+		b, _ := json.Marshal(c)
+		batch.Put([]byte(autoCursorKey), b)
+	}
+}
+
+// SortSpiral sorts the Positions passed in the order in which they appear in a square spiral around the centre
+// passed, so that positions closer to the centre come first. The resulting order does not depend on the
+// order in which the positions were passed.
+func SortSpiral(positions []Position, centre Position) {
+	slices.SortFunc(positions, func(a, b Position) int {
+		return a.SpiralIndex(centre) - b.SpiralIndex(centre)
+	})
+}
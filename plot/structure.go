@@ -0,0 +1,175 @@
+package plot
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/df-mc/dragonfly/server/world/chunk"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mcstructure is the NBT representation of a Bedrock Edition .mcstructure file, as created by structure
+// blocks. Blocks are ordered by X, then Y and then Z.
+type mcstructure struct {
+	FormatVersion int32            `nbt:"format_version"`
+	Size          []int32          `nbt:"size"`
+	Structure     structureContent `nbt:"structure"`
+	Origin        []int32          `nbt:"structure_world_origin"`
+}
+
+// structureContent holds the blocks and entities of an mcstructure.
+type structureContent struct {
+	// BlockIndices holds two layers of indices in the palette, one for regular blocks and one for liquids in
+	// the same position. An index of -1 means there is no block.
+	BlockIndices [][]int32                   `nbt:"block_indices"`
+	Entities     []map[string]any            `nbt:"entities"`
+	Palette      map[string]structurePalette `nbt:"palette"`
+}
+
+// structurePalette is the palette of an mcstructure.
+type structurePalette struct {
+	BlockPalette []map[string]any `nbt:"block_palette"`
+	// BlockPositionData holds the block entity data of blocks, indexed by the index of the block as a string.
+	BlockPositionData map[string]any `nbt:"block_position_data"`
+}
+
+// WriteStructure writes the Build to the io.Writer passed in the Bedrock Edition .mcstructure format, so that
+// it may be loaded using structure blocks.
+func (b *Build) WriteStructure(w io.Writer) error {
+	height := max(b.height, 1)
+	n := b.width * height * b.length
+	s := mcstructure{
+		FormatVersion: 1,
+		Size:          []int32{int32(b.width), int32(height), int32(b.length)},
+		Origin:        []int32{0, 0, 0},
+		Structure: structureContent{
+			BlockIndices: [][]int32{make([]int32, n), make([]int32, n)},
+			Entities:     []map[string]any{},
+		},
+	}
+	palette := structurePalette{BlockPositionData: map[string]any{}}
+	indices := map[uint32]int32{}
+	paletteIndex := func(bl world.Block) int32 {
+		rid := world.BlockRuntimeID(bl)
+		i, ok := indices[rid]
+		if !ok {
+			i = int32(len(palette.BlockPalette))
+			indices[rid] = i
+			m := encodeBlock(bl)
+			m["version"] = chunk.CurrentBlockVersion
+			palette.BlockPalette = append(palette.BlockPalette, m)
+		}
+		return i
+	}
+	for x := 0; x < b.width; x++ {
+		for y := 0; y < height; y++ {
+			for z := 0; z < b.length; z++ {
+				i := (x*height+y)*b.length + z
+				bl, liq := b.At(x, y, z, nil)
+				s.Structure.BlockIndices[0][i] = paletteIndex(bl)
+				s.Structure.BlockIndices[1][i] = -1
+				if liq != nil {
+					s.Structure.BlockIndices[1][i] = paletteIndex(liq)
+				}
+				if nbter, ok := bl.(world.NBTer); ok {
+					data := nbter.EncodeNBT()
+					data["x"], data["y"], data["z"] = int32(x), int32(y), int32(z)
+					palette.BlockPositionData[strconv.Itoa(i)] = map[string]any{"block_entity_data": data}
+				}
+			}
+		}
+	}
+	s.Structure.Palette = map[string]structurePalette{"default": palette}
+	if err := nbt.NewEncoderWithEncoding(w, nbt.LittleEndian).Encode(s); err != nil {
+		return fmt.Errorf("write structure: %w", err)
+	}
+	return nil
+}
+
+// Export writes the blocks of the plot at the Position, including block states and the data of block
+// entities, to the io.Writer passed in the Bedrock Edition .mcstructure format.
+func (pos Position) Export(tx *world.Tx, settings Settings, w io.Writer) error {
+	return CaptureBuild(tx, pos, settings).WriteStructure(w)
+}
+
+// ExportPlot exports the Plot at the Position passed to a .mcstructure file in the exports directory of the
+// DB, named by the ID of the plot, the name of its owner and the current time. The path of the file is
+// returned.
+func (db *DB) ExportPlot(tx *world.Tx, pos Position, p *Plot) (string, error) {
+	name := fmt.Sprintf("%v_%v_%v_%v.mcstructure", pos[0], pos[1], strings.ReplaceAll(p.OwnerName, " ", "_"), time.Now().Format("20060102-150405"))
+	path := filepath.Join(db.dir, "exports", name)
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return "", fmt.Errorf("export plot: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("export plot: %w", err)
+	}
+	if err := pos.Export(tx, db.settings, f); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("export plot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("export plot: %w", err)
+	}
+	return path, nil
+}
+
+// ReadStructure reads a Build from a Bedrock Edition .mcstructure file read from the io.Reader passed. Blocks
+// with unknown states are mapped to known blocks using decodeBlock and structure voids are kept as positions
+// without a block.
+func ReadStructure(r io.Reader) (*Build, error) {
+	var s mcstructure
+	if err := nbt.NewDecoderWithEncoding(r, nbt.LittleEndian).Decode(&s); err != nil {
+		return nil, fmt.Errorf("read structure: %w", err)
+	}
+	if len(s.Size) != 3 || s.Size[0] < 0 || s.Size[1] < 0 || s.Size[2] < 0 || s.Size[1] > buildHeight {
+		return nil, fmt.Errorf("read structure: invalid size %v", s.Size)
+	}
+	width, height, length := int(s.Size[0]), int(s.Size[1]), int(s.Size[2])
+	n := width * height * length
+	if len(s.Structure.BlockIndices) == 0 || len(s.Structure.BlockIndices[0]) != n {
+		return nil, fmt.Errorf("read structure: expected %v block indices", n)
+	}
+	palette := s.Structure.Palette["default"]
+	decoded := make([]world.Block, len(palette.BlockPalette))
+	for i, m := range palette.BlockPalette {
+		decoded[i] = decodeBlock(m)
+	}
+
+	b := newBuild(width, height, length)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			for z := 0; z < length; z++ {
+				si := (x*height+y)*length + z
+				var bl world.Block
+				if n := s.Structure.BlockIndices[0][si]; n >= 0 && int(n) < len(decoded) {
+					bl = decoded[n]
+				}
+				i := b.index(x, y, z)
+				if nbter, ok := bl.(world.NBTer); ok {
+					if data, ok := palette.BlockPositionData[strconv.Itoa(si)].(map[string]any); ok {
+						if entity, ok := data["block_entity_data"].(map[string]any); ok {
+							bl = nbter.DecodeNBT(entity).(world.Block)
+						}
+					}
+					b.entities[i] = bl
+				}
+				b.set(i, bl)
+				if len(s.Structure.BlockIndices) > 1 && len(s.Structure.BlockIndices[1]) == n {
+					if n := s.Structure.BlockIndices[1][si]; n >= 0 && int(n) < len(decoded) {
+						if liq, ok := decoded[n].(world.Liquid); ok {
+							b.liquids[i] = liq
+						}
+					}
+				}
+			}
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,77 @@
+package plot
+
+import (
+	"bytes"
+	"github.com/df-mc/dragonfly/server/block"
+	"github.com/df-mc/dragonfly/server/block/cube"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"testing"
+)
+
+func TestStructureRoundTrip(t *testing.T) {
+	b := newBuild(2, 2, 3)
+	for i := range b.blocks {
+		b.set(i, block.Air{})
+	}
+	b.set(b.index(0, 0, 0), block.Stone{})
+	b.set(b.index(1, 1, 2), block.Log{Wood: block.OakWood(), Axis: cube.X})
+
+	var buf bytes.Buffer
+	if err := b.WriteStructure(&buf); err != nil {
+		t.Fatalf("write structure: %v", err)
+	}
+	r, err := ReadStructure(&buf)
+	if err != nil {
+		t.Fatalf("read structure: %v", err)
+	}
+	if w, h, l := r.Size(); w != 2 || h != 2 || l != 3 {
+		t.Fatalf("structure size = %vx%vx%v, want 2x2x3", w, h, l)
+	}
+	for x := 0; x < b.width; x++ {
+		for y := 0; y < b.height; y++ {
+			for z := 0; z < b.length; z++ {
+				want, _ := b.At(x, y, z, nil)
+				if got, _ := r.At(x, y, z, nil); world.BlockRuntimeID(got) != world.BlockRuntimeID(want) {
+					t.Errorf("block at %v %v %v = %v, want %v", x, y, z, encodeBlock(got), encodeBlock(want))
+				}
+			}
+		}
+	}
+}
+
+func TestReadStructureInvalidDimensions(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		size    []int32
+		indices int
+	}{
+		{"missing size", []int32{1, 1}, 1},
+		{"negative size", []int32{-1, 1, -1}, 1},
+		{"too high", []int32{1, buildHeight + 1, 1}, buildHeight + 1},
+		{"too few indices", []int32{2, 2, 2}, 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := mcstructure{
+				FormatVersion: 1,
+				Size:          tc.size,
+				Origin:        []int32{0, 0, 0},
+				Structure: structureContent{
+					BlockIndices: [][]int32{make([]int32, tc.indices), make([]int32, tc.indices)},
+					Entities:     []map[string]any{},
+					Palette: map[string]structurePalette{"default": {
+						BlockPalette:      []map[string]any{encodeBlock(block.Stone{})},
+						BlockPositionData: map[string]any{},
+					}},
+				},
+			}
+			var buf bytes.Buffer
+			if err := nbt.NewEncoderWithEncoding(&buf, nbt.LittleEndian).Encode(s); err != nil {
+				t.Fatalf("encode structure: %v", err)
+			}
+			if _, err := ReadStructure(&buf); err == nil {
+				t.Error("ReadStructure returned no error")
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package plot
+
+import (
+	"fmt"
+	"github.com/df-mc/goleveldb/leveldb"
+	"github.com/df-mc/goleveldb/leveldb/util"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// tagPrefix is the prefix of keys that index plots by their tags. The key is followed by the tag, a colon and
+// the hash of the Position of the plot.
+const tagPrefix = "plots:tag:"
+
+// MaxTags is the maximum amount of tags that a single Plot may have.
+const MaxTags = 8
+
+// tagRegex is a regular expression that valid tags must match, such as 'medieval' or 'pixel-art'.
+var tagRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,23}$`)
+
+// ValidTag checks if the tag passed is a valid tag. Tags are 2-24 characters long and consist of lower-case
+// letters, numbers and '-'.
+func ValidTag(tag string) bool {
+	return tagRegex.MatchString(tag)
+}
+
+// tagKey returns the key under which the plot at the Position passed is indexed for the tag passed.
+func tagKey(tag string, pos Position) []byte {
+	return append([]byte(tagPrefix+tag+":"), pos.Hash()...)
+}
+
+// HasTag checks if the Plot has the tag passed.
+func (p *Plot) HasTag(tag string) bool {
+	return slices.Contains(p.Tags, tag)
+}
+
+// AddTag adds a tag to the Plot at the Position passed and stores it, indexing the plot so that it may be
+// found by searching for the tag. The tag is converted to lower case and must be valid according to ValidTag.
+func (db *DB) AddTag(pos Position, p *Plot, tag string) error {
+	tag = strings.ToLower(tag)
+	if !ValidTag(tag) {
+		return fmt.Errorf("add tag: invalid tag '%v'", tag)
+	}
+	if p.HasTag(tag) {
+		return nil
+	}
+	if len(p.Tags) >= MaxTags {
+		return fmt.Errorf("add tag: plot may have at most %v tags", MaxTags)
+	}
+	batch := new(leveldb.Batch)
+	batch.Put(tagKey(tag, pos), nil)
+	p.Tags = append(p.Tags, tag)
+	if err := db.storePlotBatch(batch, pos, p); err != nil {
+		return fmt.Errorf("add tag: %w", err)
+	}
+	return nil
+}
+
+// RemoveTag removes a tag from the Plot at the Position passed and stores it.
+func (db *DB) RemoveTag(pos Position, p *Plot, tag string) error {
+	tag = strings.ToLower(tag)
+	if !p.HasTag(tag) {
+		return nil
+	}
+	batch := new(leveldb.Batch)
+	batch.Delete(tagKey(tag, pos))
+	p.Tags = slices.DeleteFunc(p.Tags, func(other string) bool { return other == tag })
+	if err := db.storePlotBatch(batch, pos, p); err != nil {
+		return fmt.Errorf("remove tag: %w", err)
+	}
+	return nil
+}
+
+// TaggedPlots returns the Positions of all plots with the tag passed, read from the tag index.
+func (db *DB) TaggedPlots(tag string) ([]Position, error) {
+	it := db.ldb.NewIterator(util.BytesPrefix([]byte(tagPrefix+strings.ToLower(tag)+":")), nil)
+	defer it.Release()
+
+	var positions []Position
+	for it.Next() {
+		key := it.Key()
+		positions = append(positions, posFromHash(key[len(key)-8:]))
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("tagged plots: %w", err)
+	}
+	return positions, nil
+}
+
+// tagFilter is a Filter that matches plots with a specific tag. It is an IndexedFilter, so that searching
+// for plots with a tag does not require reading every plot.
+type tagFilter string
+
+// Match ...
+func (f tagFilter) Match(_ Position, p *Plot) bool {
+	return p.HasTag(string(f))
+}
+
+// Candidates ...
+func (f tagFilter) Candidates(db *DB) ([]Position, error) {
+	return db.TaggedPlots(string(f))
+}
+
+func init() {
+	RegisterFilter("tag", func(op, value string) (Filter, error) {
+		if op != ":" && op != "=" {
+			return nil, fmt.Errorf("tag only supports ':'")
+		}
+		return tagFilter(strings.ToLower(value)), nil
+	})
+}
@@ -0,0 +1,85 @@
+package plot
+
+import (
+	"fmt"
+	"github.com/df-mc/dragonfly/server/world"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// templateExtension is the extension of files in the templates directory that hold a template.
+const templateExtension = ".mcstructure"
+
+// ValidTemplateName checks if the name passed may be used as the name of a template.
+func ValidTemplateName(name string) bool {
+	return nameRegex.MatchString(name)
+}
+
+// templatePath returns the path of the file that holds the template with the name passed.
+func (db *DB) templatePath(name string) string {
+	return filepath.Join(db.dir, "templates", name+templateExtension)
+}
+
+// Templates returns the names of all templates that plots may be built from. Templates are .mcstructure files
+// in the templates directory of the DB, so that they may also be created using structure blocks.
+func (db *DB) Templates() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(db.dir, "templates"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("templates: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), templateExtension); ok && !e.IsDir() && ValidTemplateName(name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// SaveTemplate saves the blocks of the plot at the Position passed as a template with the name passed. An
+// existing template with the same name is overwritten.
+func (db *DB) SaveTemplate(tx *world.Tx, pos Position, name string) error {
+	path := db.templatePath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("save template: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("save template: %w", err)
+	}
+	if err := pos.Export(tx, db.settings, f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("save template: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("save template: %w", err)
+	}
+	return nil
+}
+
+// LoadTemplate reads the template with the name passed. os.ErrNotExist is returned if no template with the
+// name exists.
+func (db *DB) LoadTemplate(name string) (*Build, error) {
+	if !ValidTemplateName(name) {
+		return nil, fmt.Errorf("load template: %w", os.ErrNotExist)
+	}
+	f, err := os.Open(db.templatePath(name))
+	if err != nil {
+		return nil, fmt.Errorf("load template: %w", err)
+	}
+	defer f.Close()
+	return ReadStructure(f)
+}
+
+// RemoveTemplate removes the template with the name passed. os.ErrNotExist is returned if no template with
+// the name exists.
+func (db *DB) RemoveTemplate(name string) error {
+	if !ValidTemplateName(name) {
+		return fmt.Errorf("remove template: %w", os.ErrNotExist)
+	}
+	if err := os.Remove(db.templatePath(name)); err != nil {
+		return fmt.Errorf("remove template: %w", err)
+	}
+	return nil
+}